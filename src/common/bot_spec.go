@@ -0,0 +1,106 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BotSpec is the name of a bot with the values of its parameters,
+// written as name(k=v,...), e.g. random1(depth=6,discount=0.9).
+type BotSpec struct {
+	Name   string             `json:"name"`
+	Params map[string]float64 `json:"params,omitempty"`
+}
+
+func (b BotSpec) String() string {
+	if len(b.Params) == 0 {
+		return b.Name
+	}
+	names := make([]string, 0, len(b.Params))
+	for k := range b.Params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for i, k := range names {
+		names[i] = k + "=" + strconv.FormatFloat(b.Params[k], 'g', -1, 64)
+	}
+	return b.Name + "(" + strings.Join(names, ",") + ")"
+}
+
+// Parses a comma-separated list of parameters, e.g. depth=6,discount=0.9.
+func ParseParams(s string) (map[string]float64, error) {
+	params := make(map[string]float64)
+	for _, part := range strings.Split(s, ",") {
+		kv := strings.Split(part, "=")
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("bad parameter: %q", part)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad value of parameter %v: %q", kv[0], kv[1])
+		}
+		params[strings.TrimSpace(kv[0])] = v
+	}
+	return params, nil
+}
+
+// Parses a single bot, name or name(k=v,...).
+func ParseBotSpec(s string) (b BotSpec, err error) {
+	s = strings.TrimSpace(s)
+	open := strings.Index(s, "(")
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		b.Name = s
+	} else {
+		b.Name = s[:open]
+		if params := s[open+1 : len(s)-1]; strings.TrimSpace(params) != "" {
+			if b.Params, err = ParseParams(params); err != nil {
+				return b, fmt.Errorf("bot %v: %v", b.Name, err)
+			}
+		}
+	}
+	if b.Name == "" {
+		return b, fmt.Errorf("bad bot: %q", s)
+	}
+	return b, nil
+}
+
+// Parses a comma-separated list of bots, each optionally repeated with
+// *N, e.g. random1(depth=6,discount=0.9)*4,m(futureMaxDist=10).
+func ParseBotSpecs(s string) (bots []BotSpec, err error) {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, s[start:])
+
+	for _, part := range parts {
+		n := 1
+		if i := strings.LastIndex(part, "*"); i > strings.LastIndex(part, ")") {
+			if n, err = strconv.Atoi(strings.TrimSpace(part[i+1:])); err != nil || n < 1 {
+				return nil, fmt.Errorf("bad number of bots in %q", part)
+			}
+			part = part[:i]
+		}
+		b, err := ParseBotSpec(part)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			bots = append(bots, b)
+		}
+	}
+	return bots, nil
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseBotSpecs(t *testing.T) {
+	bots, err := ParseBotSpecs("random1(depth=6,discount=0.9)*2,m(futureMaxDist=10),scripted:pass;10 11,zombie*1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	random1 := BotSpec{Name: "random1", Params: map[string]float64{"depth": 6, "discount": 0.9}}
+	want := []BotSpec{
+		random1,
+		random1,
+		{Name: "m", Params: map[string]float64{"futureMaxDist": 10}},
+		{Name: "scripted:pass;10 11"},
+		{Name: "zombie"},
+	}
+	if !reflect.DeepEqual(bots, want) {
+		t.Errorf("got %+v, want %+v", bots, want)
+	}
+	if s := random1.String(); s != "random1(depth=6,discount=0.9)" {
+		t.Errorf("string: got %q", s)
+	}
+
+	for _, bad := range []string{"random1*x", "random1*0", "random1(depth)", "random1(depth=x)", "(depth=1)", ""} {
+		if _, err := ParseBotSpecs(bad); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
+
+func TestPlayerProxyParams(t *testing.T) {
+	if _, err := MakePlayerProxyWithParams("random1", map[string]float64{"nonsense": 1}); err == nil {
+		t.Error("unknown parameter: no error")
+	}
+
+	pp, err := MakePlayerProxyWithParams("m", map[string]float64{"futureMaxDist": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := pp.Spec().String(); s != "m(futureMaxDist=2)" {
+		t.Errorf("spec: got %q", s)
+	}
+
+	// The parameters are in the offline state.
+	state, err := json.Marshal(&pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPlayerProxy(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Params, pp.Params) {
+		t.Errorf("params after loading: got %v, want %v", loaded.Params, pp.Params)
+	}
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"game"
+	"io/ioutil"
+)
+
+func ReadMap(path string) (m Map, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return m, fmt.Errorf("can't read file %v: %v", path, err)
+	}
+
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return m, fmt.Errorf("can't parse map %v: %v", path, err)
+	}
+	return m, nil
+}
+
+// Returns the list of problems that make the map unplayable: duplicate
+// sites, dangling or duplicate rivers, self-loops and mines that are
+// not sites. Disconnected maps are still valid.
+func (m *Map) Validate() (problems []string) {
+	sites := make(map[int]bool)
+	for _, s := range m.Sites {
+		if sites[s.Id] {
+			problems = append(problems, fmt.Sprintf("duplicate site id %v", s.Id))
+		}
+		sites[s.Id] = true
+	}
+
+	rivers := make(map[[2]int]bool)
+	for i, r := range m.Rivers {
+		if !sites[r.Source] {
+			problems = append(problems, fmt.Sprintf("river #%v (%v, %v): unknown source site", i, r.Source, r.Target))
+		}
+		if !sites[r.Target] {
+			problems = append(problems, fmt.Sprintf("river #%v (%v, %v): unknown target site", i, r.Source, r.Target))
+		}
+		if r.Source == r.Target {
+			problems = append(problems, fmt.Sprintf("river #%v (%v, %v): self-loop", i, r.Source, r.Target))
+			continue
+		}
+
+		key := [2]int{r.Source, r.Target}
+		if key[0] > key[1] {
+			key[0], key[1] = key[1], key[0]
+		}
+		if rivers[key] {
+			problems = append(problems, fmt.Sprintf("river #%v (%v, %v): duplicate river", i, r.Source, r.Target))
+		}
+		rivers[key] = true
+	}
+
+	mines := make(map[int]bool)
+	for _, mine := range m.Mines {
+		if !sites[mine] {
+			problems = append(problems, fmt.Sprintf("mine %v is not a site", mine))
+		}
+		if mines[mine] {
+			problems = append(problems, fmt.Sprintf("duplicate mine %v", mine))
+		}
+		mines[mine] = true
+	}
+
+	return
+}
+
+// Converts the map to the compressed format. The index must be set up on
+// the map's sites.
+func MakeGameMap(m *Map, index *CompressedIndex) (gm game.Map) {
+	gm.Sites = make([]int, len(index.Backward))
+	for i, site := range m.Sites {
+		gm.Sites[i] = index.Forward[site.Id]
+	}
+
+	gm.Rivers = make([]game.River, len(m.Rivers))
+	for i, river := range m.Rivers {
+		gm.Rivers[i].Source = index.Forward[river.Source]
+		gm.Rivers[i].Target = index.Forward[river.Target]
+	}
+
+	gm.Mines = make([]int, len(m.Mines))
+	for i, mine := range m.Mines {
+		gm.Mines[i] = index.Forward[mine]
+	}
+	return
+}
@@ -29,11 +29,21 @@ func (m *SplurgeMove) String() string {
 	return fmt.Sprintf("Punter=%v, Splurge Route=%v", m.Punter, m.Route)
 }
 
+type OptionMove struct {
+	Punter int `json:"punter"`
+	Source int `json:"source"`
+	Target int `json:"target"`
+}
+
+func (m *OptionMove) String() string {
+	return fmt.Sprintf("Punter=%v, Option River=(%v, %v)", m.Punter, m.Source, m.Target)
+}
+
 type Move struct {
 	Claim   *ClaimMove   `json:"claim,omitempty"`
 	Pass    *PassMove    `json:"pass,omitempty"`
 	Splurge *SplurgeMove `json:"splurge,omitempty"`
-	State   *PlayerProxy `json:"state"`
+	Option  *OptionMove  `json:"option,omitempty"`
 }
 
 func (m *Move) String() string {
@@ -46,5 +56,8 @@ func (m *Move) String() string {
 	if m.Splurge != nil {
 		return m.Splurge.String()
 	}
+	if m.Option != nil {
+		return m.Option.String()
+	}
 	return "Bad Move"
 }
@@ -1,35 +1,45 @@
 package common
 
 import (
+	"encoding/json"
+	"fmt"
 	"game"
 	"log"
 )
 
 type PlayerProxy struct {
-	Player game.Player     `json:"player"`
-	Index  CompressedIndex `json:"index"`
+	Bot    string             `json:"bot"`              // the name the player was made with
+	Params map[string]float64 `json:"params,omitempty"` // and the parameters given to it
+	Player game.Player        `json:"player"`
+	Index  CompressedIndex    `json:"index"`
+	Timing Timing             `json:"timing"`
 }
 
-func (pp *PlayerProxy) toGameMove(move *Move) game.Move {
+// Converts the move with the site ids from the map to the compressed format.
+func (index *CompressedIndex) GameMove(move *Move) game.Move {
 	if move.Pass != nil {
 		return game.MakePassMove(move.Pass.Punter)
 	}
 	if move.Splurge != nil {
 		route := make([]int, len(move.Splurge.Route))
 		for i, v := range move.Splurge.Route {
-			route[i] = pp.Index.Forward[v]
+			route[i] = index.Forward[v]
 		}
 		return game.MakeSplurgeMove(move.Splurge.Punter, route)
 	}
+	if move.Option != nil {
+		option := move.Option
+		return game.MakeOptionMove(option.Punter, index.Forward[option.Source], index.Forward[option.Target])
+	}
 	claim := move.Claim
-	return game.MakeClaimMove(claim.Punter, pp.Index.Forward[claim.Source], pp.Index.Forward[claim.Target])
+	return game.MakeClaimMove(claim.Punter, index.Forward[claim.Source], index.Forward[claim.Target])
 }
 
 func (pp *PlayerProxy) toGameMoves(moves []Move) (gmoves []game.Move) {
 	n := len(moves)
 	gmoves = make([]game.Move, n, n)
 	for i, m := range moves {
-		gmoves[i] = pp.toGameMove(&m)
+		gmoves[i] = pp.Index.GameMove(&m)
 	}
 	return
 }
@@ -51,10 +61,14 @@ func (pp *PlayerProxy) fromGameMove(m *game.Move) (r Move) {
 		r.Splurge = &SplurgeMove{
 			Punter: m.Punter,
 			Route:  route}
+	case game.Option:
+		r.Option = &OptionMove{
+			Punter: m.Punter,
+			Source: pp.Index.Backward[m.Source],
+			Target: pp.Index.Backward[m.Target]}
 	default:
 		log.Fatal("Unknown move type:", m.Type)
 	}
-	r.State = pp
 	return
 }
 
@@ -65,25 +79,10 @@ func (pp *PlayerProxy) Setup(punter, punters int, m *Map, settings game.Settings
 	}
 	pp.Index.Setup(allSites)
 
-	var gm game.Map
-
-	gm.Sites = make([]int, len(pp.Index.Backward))
-	for i, site := range m.Sites {
-		gm.Sites[i] = pp.Index.Forward[site.Id]
+	pp.Player.Setup(punter, punters, MakeGameMap(m, &pp.Index), settings)
+	if sa, ok := pp.Player.(game.SiteIdsAware); ok {
+		sa.SetSiteIds(pp.Index.Backward)
 	}
-
-	gm.Rivers = make([]game.River, len(m.Rivers))
-	for i, river := range m.Rivers {
-		gm.Rivers[i].Source = pp.Index.Forward[river.Source]
-		gm.Rivers[i].Target = pp.Index.Forward[river.Target]
-	}
-
-	gm.Mines = make([]int, len(m.Mines))
-	for i, mine := range m.Mines {
-		gm.Mines[i] = pp.Index.Forward[mine]
-	}
-
-	pp.Player.Setup(punter, punters, gm, settings)
 }
 
 func (pp *PlayerProxy) MakeMove(moves []Move) Move {
@@ -95,6 +94,11 @@ func (pp *PlayerProxy) Name() string {
 	return pp.Player.Name()
 }
 
+// Returns the name of the player with the parameters it was given.
+func (pp *PlayerProxy) Spec() BotSpec {
+	return BotSpec{Name: pp.Name(), Params: pp.Params}
+}
+
 func (pp *PlayerProxy) GetPunter() int {
 	return pp.Player.GetPunter()
 }
@@ -110,6 +114,36 @@ func (pp *PlayerProxy) GetFutures() []game.Future {
 }
 
 func MakePlayerProxy(name string) (pp PlayerProxy) {
+	pp.Bot = name
 	pp.Player = game.MakePlayer(name)
 	return
 }
+
+func MakePlayerProxyWithParams(name string, params map[string]float64) (pp PlayerProxy, err error) {
+	pp.Bot = name
+	pp.Params = params
+	pp.Player, err = game.MakePlayerWithParams(name, params)
+	return
+}
+
+// Restores the proxy from the offline state, making the same bot with the
+// same parameters that were chosen at setup.
+func LoadPlayerProxy(state []byte) (pp PlayerProxy, err error) {
+	var header struct {
+		Bot    string             `json:"bot"`
+		Params map[string]float64 `json:"params"`
+	}
+	if err = json.Unmarshal(state, &header); err != nil {
+		return pp, fmt.Errorf("can't load state: %v", err)
+	}
+	pp.Bot = header.Bot
+	pp.Params = header.Params
+	pp.Player, err = game.MakePlayerWithParams(header.Bot, header.Params)
+	if err != nil {
+		return pp, fmt.Errorf("can't load state: %v", err)
+	}
+	if err = json.Unmarshal(state, &pp); err != nil {
+		return pp, fmt.Errorf("can't load state: %v", err)
+	}
+	return pp, nil
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"game"
+	"time"
+)
+
+const (
+	MoveTimeLimit = time.Second // the time limit for a move in the problem statement
+	minMoveBudget = 50 * time.Millisecond
+	timeReserve   = 0.3 // share of the time limit left for the server and the system
+	maxTimeouts   = 2   // after this many timeouts the bot is downgraded
+	slowMove      = 0.8 // share of the usable time that makes the last move close to a timeout
+)
+
+// Timing keeps track of the time the moves take in the offline mode,
+// where every move is made by a new process.
+type Timing struct {
+	Timeouts int     `json:"timeouts"` // moves that the server replaced with a pass
+	LastMove float64 `json:"lastMove"` // seconds from the start of the process to the reply
+	Claimed  bool    `json:"claimed"`  // the last move was not a pass
+}
+
+// Counts a timeout if the server reports a pass for our last move.
+func (t *Timing) Observe(punter int, moves []Move) {
+	for _, m := range moves {
+		if m.Pass != nil && m.Pass.Punter == punter && t.Claimed {
+			t.Timeouts++
+		}
+	}
+}
+
+// Returns the time the bot may spend on the move, given the time already
+// spent on loading the state. Saving the state takes about as long as
+// loading it. The budget is halved if the last move came close to the
+// time limit, before the server replaces it with a pass, and for every
+// timeout.
+func (t *Timing) Budget(spent time.Duration) time.Duration {
+	usable := time.Duration(float64(MoveTimeLimit) * (1 - timeReserve))
+	budget := usable - 2*spent
+	if t.LastMove > slowMove*usable.Seconds() {
+		budget /= 2
+	}
+	for i := 0; i < t.Timeouts; i++ {
+		budget /= 2
+	}
+	if budget < minMoveBudget {
+		budget = minMoveBudget
+	}
+	return budget
+}
+
+func (t *Timing) Record(move *Move, spent time.Duration) {
+	t.Claimed = move.Pass == nil
+	t.LastMove = spent.Seconds()
+}
+
+// Passes the time budget to the bot and replaces the bot with a cheaper
+// one after too many timeouts. Returns true if the bot was replaced.
+func (pp *PlayerProxy) SetTimeBudget(budget time.Duration) (downgraded bool) {
+	if pp.Timing.Timeouts >= maxTimeouts {
+		if d, ok := pp.Player.(game.Downgradable); ok {
+			pp.Player = d.Downgrade()
+			pp.Bot = pp.Player.Name()
+			pp.Params = nil // the parameters were the old bot's
+			downgraded = true
+		}
+	}
+	if t, ok := pp.Player.(game.TimeLimited); ok {
+		t.SetTimeBudget(budget)
+	}
+	return
+}
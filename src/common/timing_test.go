@@ -0,0 +1,99 @@
+package common
+
+import (
+	"encoding/json"
+	"game"
+	"testing"
+	"time"
+)
+
+func TestTimingTimeouts(t *testing.T) {
+	var timing Timing
+	claim := Move{Claim: &ClaimMove{Punter: 1, Source: 1, Target: 2}}
+	pass := Move{Pass: &PassMove{Punter: 1}}
+
+	timing.Observe(1, []Move{pass, pass})
+	if timing.Timeouts != 0 {
+		t.Errorf("timeouts before the first move: got %v, want 0", timing.Timeouts)
+	}
+
+	full := timing.Budget(0)
+	timing.Record(&claim, 100*time.Millisecond)
+	timing.Observe(1, []Move{{Pass: &PassMove{Punter: 0}}, pass})
+	if timing.Timeouts != 1 {
+		t.Errorf("timeouts after a lost claim: got %v, want 1", timing.Timeouts)
+	}
+	if b := timing.Budget(0); b != full/2 {
+		t.Errorf("budget after a timeout: got %v, want %v", b, full/2)
+	}
+	if b := timing.Budget(time.Second); b != minMoveBudget {
+		t.Errorf("budget when out of time: got %v, want %v", b, minMoveBudget)
+	}
+
+	timing.Record(&pass, 0)
+	timing.Observe(1, []Move{pass})
+	if timing.Timeouts != 1 {
+		t.Errorf("timeouts after our own pass: got %v, want 1", timing.Timeouts)
+	}
+}
+
+func TestTimingSlowMove(t *testing.T) {
+	var timing Timing
+	claim := Move{Claim: &ClaimMove{Punter: 1, Source: 1, Target: 2}}
+	full := timing.Budget(0)
+
+	// A move within the limit but close to it halves the next budget.
+	timing.Record(&claim, 600*time.Millisecond)
+	timing.Observe(1, []Move{claim})
+	if b := timing.Budget(0); timing.Timeouts != 0 || b != full/2 {
+		t.Errorf("budget after a slow move: got %v, want %v", b, full/2)
+	}
+
+	timing.Record(&claim, 100*time.Millisecond)
+	if b := timing.Budget(0); b != full {
+		t.Errorf("budget after a fast move: got %v, want %v", b, full)
+	}
+}
+
+func TestDowngrade(t *testing.T) {
+	pp := MakePlayerProxy("random1")
+	if pp.SetTimeBudget(time.Second) {
+		t.Error("downgraded without timeouts")
+	}
+
+	pp.Timing.Timeouts = maxTimeouts
+	if !pp.SetTimeBudget(time.Second) || pp.Bot != "baseline" {
+		t.Errorf("bot after %v timeouts: got %v, want baseline", maxTimeouts, pp.Bot)
+	}
+	if _, ok := pp.Player.(*game.BaselinePlayer); !ok {
+		t.Errorf("player after downgrade: got %T", pp.Player)
+	}
+	if pp.SetTimeBudget(time.Second) {
+		t.Error("baseline was downgraded")
+	}
+}
+
+func TestDowngradeState(t *testing.T) {
+	pp, err := MakePlayerProxyWithParams("random1", map[string]float64{"depth": 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := Map{Sites: []Site{{Id: 1}, {Id: 2}}, Rivers: []game.River{{Source: 1, Target: 2}}, Mines: []int{1}}
+	pp.Setup(0, 2, &m, game.Settings{})
+	pp.Timing.Timeouts = maxTimeouts
+	if !pp.SetTimeBudget(time.Second) || pp.Params != nil {
+		t.Fatalf("after the downgrade: bot %v, params %v", pp.Bot, pp.Params)
+	}
+
+	state, err := json.Marshal(&pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadPlayerProxy(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Bot != "baseline" || loaded.Params != nil {
+		t.Errorf("loaded %v with %v, want baseline without parameters", loaded.Bot, loaded.Params)
+	}
+}
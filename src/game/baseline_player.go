@@ -14,6 +14,8 @@ type BaselinePlayer struct {
 	reachableFromMine [][]bool // reachableFromMine[i] is the reachability array from Mine i
 	score             int64    // current score
 	scores            []int64  // current scores for all punters
+	scorer            Scorer   // scorer for the current position of the punter
+	futureDist        [][]int  // futureDist[k][c] is the distance over free rivers from component c to the target of future k
 }
 
 func (p *BaselinePlayer) MakeClaimMove(source, target int) Move {
@@ -33,9 +35,18 @@ func (p *BaselinePlayer) MakeSplurgeMove(route []int) Move {
 	if len(route) > p.Passes+2 {
 		panic("not enough passes to splurge")
 	}
+	p.Passes = 0
 	return MakeSplurgeMove(p.Punter, route)
 }
 
+func (p *BaselinePlayer) MakeOptionMove(source, target int) Move {
+	if !p.Settings.OptionsMode {
+		panic("cannot buy an option: options mode is off")
+	}
+	p.Passes = 0
+	return MakeOptionMove(p.Punter, source, target)
+}
+
 func (p *BaselinePlayer) Setup(punter, punters int, m Map, s Settings) {
 	p.Punter = punter
 	p.Punters = punters
@@ -48,6 +59,7 @@ func (p *BaselinePlayer) PrepareForMove(moves []Move) {
 	p.ApplyMoves(moves)
 	p.CalcReachabilityFromMines()
 	p.CalcScores()
+	p.CalcFutureDistances()
 }
 
 func (p *BaselinePlayer) MakeMove(moves []Move) Move {
@@ -74,17 +86,8 @@ func (p *BaselinePlayer) GetFutures() []Future {
 	return p.Futures
 }
 
-func (p *BaselinePlayer) SetEdgeOwnership(a, b, owner int) {
-	for _, eId := range p.Edges[a] {
-		e := &p.AllEdges[eId]
-		if e.Dst == b {
-			if e.Owner >= 0 && e.Owner != owner {
-				panic("a previously claimed edge was claimed in a non-pass move")
-			}
-			e.Owner = owner
-			p.AllEdges[e.Id^1].Owner = owner
-		}
-	}
+func (p *BaselinePlayer) SetFutures(futures []Future) {
+	p.Futures = futures
 }
 
 func (p *BaselinePlayer) ApplyMoves(moves []Move) {
@@ -97,6 +100,10 @@ func (p *BaselinePlayer) ApplyMoves(moves []Move) {
 			p.SetEdgeOwnership(m.Source, m.Target, m.Punter)
 		}
 
+		if m.Type == Option {
+			p.SetEdgeOption(m.Source, m.Target, m.Punter)
+		}
+
 		if m.Type == Splurge {
 			for i := 0; i+1 < len(m.Route); i++ {
 				p.SetEdgeOwnership(m.Route[i], m.Route[i+1], m.Punter)
@@ -116,20 +123,13 @@ func (p *BaselinePlayer) CalcReachabilityFromMines() {
 func (p *BaselinePlayer) CalcScores() {
 	p.scores = make([]int64, p.Punters)
 	for pId := 0; pId < p.Punters; pId++ {
-		for i := range p.Mines {
-			var was []bool
-			if pId == p.Punter {
-				was = p.reachableFromMine[i]
-			} else {
-				was = make([]bool, p.NumSites)
-				p.Dfs(p.Mines[i], pId, was)
-			}
-			for j := 0; j < p.NumSites; j++ {
-				if was[j] {
-					d := int64(p.Distance[i][j])
-					p.scores[pId] += d * d
-				}
-			}
+		if pId == p.Punter {
+			p.scorer = MakeScorer(&p.Graph, pId, p.Futures)
+			p.scores[pId] = p.scorer.Score()
+		} else {
+			// Futures of other punters are unknown.
+			s := MakeScorer(&p.Graph, pId, nil)
+			p.scores[pId] = s.Score()
 		}
 	}
 	p.score = p.scores[p.Punter]
@@ -137,6 +137,13 @@ func (p *BaselinePlayer) CalcScores() {
 
 // Returns the edge that results in the best increase in score.
 func (p *BaselinePlayer) FindEdge() (int, int, bool) {
+	// A future the others can cut off with a single river is defended
+	// before it's too late.
+	if vs := p.FutureVulnerabilities(2); len(vs) > 0 {
+		e := &p.AllEdges[vs[0].Rivers[0]]
+		return e.Src, e.Dst, true
+	}
+
 	bestU, bestV, bestInc := -1, -1, int64(0)
 
 	for _, e := range p.AllEdges {
@@ -144,20 +151,7 @@ func (p *BaselinePlayer) FindEdge() (int, int, bool) {
 			continue
 		}
 
-		var curInc int64
-		for i := range p.Mines {
-			rS := p.reachableFromMine[i][e.Src]
-			rD := p.reachableFromMine[i][e.Dst]
-			if rS == rD {
-				continue
-			}
-			d := int64(p.Distance[i][e.Src])
-			if rS {
-				d = int64(p.Distance[i][e.Dst])
-			}
-			curInc += d * d
-		}
-
+		curInc := p.scorer.ClaimGain(e.Src, e.Dst) + p.FutureProgress(e.Src, e.Dst)
 		if bestInc < curInc {
 			bestInc = curInc
 			bestU, bestV = e.Src, e.Dst
@@ -170,3 +164,52 @@ func (p *BaselinePlayer) FindEdge() (int, int, bool) {
 
 	return 0, 0, false
 }
+
+func (p *BaselinePlayer) CalcFutureDistances() {
+	p.futureDist = make([][]int, len(p.Futures))
+	for k, f := range p.Futures {
+		if p.scorer.Connected(f.Src, f.Dst) {
+			continue
+		}
+		p.futureDist[k] = make([]int, p.NumSites)
+		for c := range p.futureDist[k] {
+			p.futureDist[k][c] = -1
+		}
+		for v, d := range p.FreeDistance(f.Dst, p.Punter) {
+			c := p.scorer.Component(v)
+			if d >= 0 && (p.futureDist[k][c] < 0 || d < p.futureDist[k][c]) {
+				p.futureDist[k][c] = d
+			}
+		}
+	}
+}
+
+// Returns the share of the unfinished futures' value earned by claiming
+// the river (u, v) that brings a mine's component closer to the future's
+// target. Completing a future is counted by the scorer, not here.
+func (p *BaselinePlayer) FutureProgress(u, v int) (progress int64) {
+	cu, cv := p.scorer.Component(u), p.scorer.Component(v)
+	if cu == cv {
+		return
+	}
+	for k, f := range p.Futures {
+		dist := p.futureDist[k]
+		if dist == nil {
+			continue
+		}
+		cm := p.scorer.Component(f.Src)
+		other := cu
+		if cu == cm {
+			other = cv
+		} else if cv != cm {
+			continue
+		}
+		cur, next := dist[cm], dist[other]
+		if cur <= 0 || next <= 0 || next >= cur {
+			continue
+		}
+		d3 := -p.scorer.FutureScore(f)
+		progress += 2 * d3 * int64(cur-next) / int64(cur)
+	}
+	return
+}
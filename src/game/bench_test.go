@@ -0,0 +1,135 @@
+package game_test
+
+import (
+	"common"
+	"encoding/json"
+	"game"
+	"playground/arena"
+	"testing"
+)
+
+// A map from maps/ with the mid-game position on it from
+// suites/bench.jsonl: two punters have claimed the rivers of the mines and
+// at least half of the rivers, growing their networks from the mines.
+type benchMap struct {
+	name  string
+	m     common.Map
+	gm    game.Map
+	index common.CompressedIndex
+	moves []game.Move // in the compressed format
+}
+
+var benchMaps []benchMap
+
+func loadBenchMaps(b *testing.B) []benchMap {
+	if benchMaps != nil {
+		return benchMaps
+	}
+	suite, err := arena.LoadSuite("../../suites/bench.jsonl")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, pos := range suite {
+		bm := benchMap{name: pos.Name, m: *pos.GameMap()}
+		sites := make([]int, len(bm.m.Sites))
+		for i, s := range bm.m.Sites {
+			sites[i] = s.Id
+		}
+		bm.index.Setup(sites)
+		bm.gm = common.MakeGameMap(&bm.m, &bm.index)
+		for p, rivers := range pos.Claimed {
+			for _, r := range rivers {
+				bm.moves = append(bm.moves, game.MakeClaimMove(p, bm.index.Forward[r[0]], bm.index.Forward[r[1]]))
+			}
+		}
+		benchMaps = append(benchMaps, bm)
+	}
+	return benchMaps
+}
+
+func BenchmarkInitGraph(b *testing.B) {
+	for _, bm := range loadBenchMaps(b) {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var g game.Graph
+				g.InitGraph(bm.gm)
+			}
+		})
+	}
+}
+
+func setupBaseline(bm *benchMap) *game.BaselinePlayer {
+	p := new(game.BaselinePlayer)
+	p.Setup(0, 2, bm.gm, game.Settings{})
+	p.PrepareForMove(bm.moves)
+	return p
+}
+
+func BenchmarkPrepareForMove(b *testing.B) {
+	for _, bm := range loadBenchMaps(b) {
+		p := setupBaseline(&bm)
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p.PrepareForMove(bm.moves)
+			}
+		})
+	}
+}
+
+func BenchmarkFindEdge(b *testing.B) {
+	for _, bm := range loadBenchMaps(b) {
+		p := setupBaseline(&bm)
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p.FindEdge()
+			}
+		})
+	}
+}
+
+func BenchmarkRandom1MakeMove(b *testing.B) {
+	for _, bm := range loadBenchMaps(b) {
+		p := game.MakePlayer("random1")
+		p.Setup(0, 2, bm.gm, game.Settings{})
+		// The bot would claim a free river of a mine without a search.
+		bp := setupBaseline(&bm)
+		for _, e := range bp.AllEdges {
+			for _, mine := range bp.Mines {
+				if e.Owner < 0 && e.Src == mine {
+					b.Fatalf("%v: river (%v, %v) of a mine is free", bm.name, e.Src, e.Dst)
+				}
+			}
+		}
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p.MakeMove(bm.moves)
+			}
+		})
+	}
+}
+
+// The offline mode saves the state after every move and loads it before
+// the next one.
+func BenchmarkStateRoundTrip(b *testing.B) {
+	for _, bm := range loadBenchMaps(b) {
+		pp := common.MakePlayerProxy("random1")
+		pp.Setup(0, 2, &bm.m, game.Settings{})
+		pp.Player.MakeMove(bm.moves)
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				state, err := json.Marshal(&pp)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := common.LoadPlayerProxy(state); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Composition is a bot made of weighted evaluators and a selector. The
+// weights of all evaluators, zero for the ones not in Terms, and the
+// selector parameters are the parameters of the bot.
+type Composition struct {
+	Terms    []Term
+	Selector string             // argmax, softmax or topk
+	Defaults map[string]float64 // selector parameters that differ from the selector's defaults
+}
+
+// The weights of the combo bots, which differ only in the selector.
+var comboTerms = []Term{{"gain", 1}, {"bonus", 0.1}, {"freedom", 1}, {"mine", 10}, {"defense", 1}}
+
+// The bots made of evaluators, by name. A new bot of this kind only
+// needs an entry here.
+var compositions = map[string]Composition{
+	"combo":     {Terms: comboTerms, Selector: "argmax"},
+	"softcombo": {Terms: comboTerms, Selector: "softmax"},
+	"lookahead": {Terms: comboTerms, Selector: "topk"},
+}
+
+// Returns the names of the bots made of evaluators, sorted.
+func CompositionNames() []string {
+	names := make([]string, 0, len(compositions))
+	for name := range compositions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ComposedPlayer plays the composition it is named after: it scores the
+// free rivers next to its network with the evaluators and claims the one
+// the selector picks.
+type ComposedPlayer struct {
+	BaselinePlayer
+	Bot    string             `json:"bot"`    // the name of the composition
+	Values map[string]float64 `json:"values"` // the weights of the evaluators and the selector parameters
+}
+
+func (p *ComposedPlayer) Name() string { return p.Bot }
+
+func (p *ComposedPlayer) Params() []Param {
+	c := compositions[p.Bot]
+	var params []Param
+	for _, name := range evaluatorNames {
+		weight := 0.0
+		for _, t := range c.Terms {
+			if t.Evaluator == name {
+				weight = t.Weight
+			}
+		}
+		params = append(params, Param{Name: name, Default: weight, Doc: "weight of the " + name + " evaluator", Min: 0, Max: 100})
+	}
+	for _, sp := range selectorParams(c.Selector) {
+		if d, ok := c.Defaults[sp.Name]; ok {
+			sp.Default = d
+		}
+		params = append(params, sp)
+	}
+	return append(params, seedParam("seed of the random choices"))
+}
+
+func (p *ComposedPlayer) SetParam(name string, value float64) {
+	if p.Values == nil {
+		p.Values = make(map[string]float64)
+	}
+	p.Values[name] = value
+}
+
+func (p *ComposedPlayer) MakeMove(moves []Move) Move {
+	p.PrepareForMove(moves)
+
+	c := compositions[p.Bot]
+	terms := make([]Term, len(evaluatorNames))
+	for i, name := range evaluatorNames {
+		terms[i] = Term{Evaluator: name, Weight: p.Values[name]}
+	}
+	ev, err := MakeWeighted(terms)
+	if err != nil {
+		panic(err.Error())
+	}
+	sel, err := newSelector(c.Selector, p.Values)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	ev.Prepare(&p.BaselinePlayer)
+	r := rand.New(rand.NewSource(int64(p.Values["seed"])))
+	e := sel.Select(&p.BaselinePlayer, &ev, p.FreeRiversNearby(), r)
+	if e == nil {
+		return p.MakePassMove()
+	}
+	return p.MakeClaimMove(e.Src, e.Dst)
+}
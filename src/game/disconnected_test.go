@@ -0,0 +1,135 @@
+package game
+
+import "testing"
+
+// Two components with mines and an isolated mine:
+//
+//	0 - 1 - 2 - 3      4 - 5 - 6      7
+//	                    \_____/
+//
+// Mines are 0, 4, 6 and 7.
+func disconnectedMap() Map {
+	return Map{
+		Sites: []int{0, 1, 2, 3, 4, 5, 6, 7},
+		Rivers: []River{
+			{Source: 0, Target: 1},
+			{Source: 1, Target: 2},
+			{Source: 2, Target: 3},
+			{Source: 4, Target: 5},
+			{Source: 5, Target: 6},
+			{Source: 4, Target: 6},
+		},
+		Mines: []int{0, 4, 6, 7},
+	}
+}
+
+var allPlayers = []string{"zombie", "baseline", "greedy0", "random0", "random1", "random2", "m", "steiner", "voronoi", "combo", "softcombo", "lookahead", "learned", "meta", "endgame:random1"}
+
+func TestDisconnectedDistances(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+
+	if d := g.Distance[0][5]; d != -1 {
+		t.Errorf("distance from mine 0 to site 5: got %v, want -1", d)
+	}
+	if s := g.SiteScore(0, 5); s != 0 {
+		t.Errorf("score of unreachable site: got %v, want 0", s)
+	}
+	if s := g.SiteScore(0, 3); s != 9 {
+		t.Errorf("score of site 3 for mine 0: got %v, want 9", s)
+	}
+	if s := g.ScoreUpperBound(); s != 18 {
+		t.Errorf("score upper bound: got %v, want 18", s)
+	}
+	if s := g.FutureUpperBound(); s != 27 {
+		t.Errorf("future upper bound: got %v, want 27", s)
+	}
+}
+
+func TestDisconnectedCalcScores(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	p.PrepareForMove([]Move{
+		MakeClaimMove(0, 0, 1),
+		MakeClaimMove(1, 4, 5),
+		MakeClaimMove(0, 1, 2),
+		MakeClaimMove(1, 5, 6),
+	})
+
+	// Punter 0: sites 1 and 2 for mine 0.
+	// Punter 1: site 5 for mines 4 and 6, sites 4 and 6 for each other.
+	if p.scores[0] != 5 || p.scores[1] != 4 {
+		t.Errorf("scores: got %v, want [5 4]", p.scores)
+	}
+}
+
+func TestDisconnectedGreedy0(t *testing.T) {
+	var p Greedy0Player
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	m := p.MakeMove(nil)
+
+	// River (4, 6) joins mines 4 and 6, 1 for each, site 5 is worth 1 to
+	// either of them alone, site 1 to mine 0.
+	if m.Type != Claim || m.Source != 4 || m.Target != 6 {
+		t.Errorf("got %v, want claim of (4, 6)", m)
+	}
+
+	// With the option on (0, 1) site 2 is worth 4 to mine 0.
+	p.Setup(0, 2, disconnectedMap(), Settings{OptionsMode: true})
+	m = p.MakeMove([]Move{MakeClaimMove(1, 0, 1), MakeOptionMove(0, 0, 1)})
+	if m.Type != Claim || m.Source != 1 || m.Target != 2 {
+		t.Errorf("after the option: got %v, want claim of (1, 2)", m)
+	}
+}
+
+func TestDisconnectedFutures(t *testing.T) {
+	for _, name := range allPlayers {
+		p := MakePlayer(name)
+		m := disconnectedMap()
+		p.Setup(0, 2, m, Settings{FuturesMode: true})
+
+		var g Graph
+		g.InitGraph(m)
+		for _, f := range p.GetFutures() {
+			for i, mine := range g.Mines {
+				if mine == f.Src && g.Distance[i][f.Dst] <= 0 {
+					t.Errorf("%v: future %v is unreachable", name, f)
+				}
+			}
+		}
+	}
+}
+
+func TestDisconnectedGame(t *testing.T) {
+	m := disconnectedMap()
+	for _, name := range allPlayers {
+		players := []Player{MakePlayer(name), MakePlayer("baseline")}
+		for i, p := range players {
+			p.Setup(i, len(players), m, Settings{})
+		}
+
+		var g Graph
+		g.InitGraph(m)
+
+		moves := []Move{MakePassMove(0), MakePassMove(1)}
+		for turn := 0; turn < len(m.Rivers); turn++ {
+			i := turn % len(players)
+			move := players[i].MakeMove(moves)
+			if move.Type == Claim {
+				found := false
+				for _, eId := range g.Edges[move.Source] {
+					e := &g.AllEdges[eId]
+					if e.Dst == move.Target && e.Owner < 0 {
+						e.Owner = i
+						g.AllEdges[eId^1].Owner = i
+						found = true
+					}
+				}
+				if !found {
+					t.Fatalf("%v: punter %v claimed an unavailable river: %v", name, i, move)
+				}
+			}
+			moves[i] = move
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package game
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+const (
+	// The prefix of the names of bots with the endgame solver, e.g.
+	// "endgame:random1".
+	EndgamePrefix = "endgame:"
+
+	endgameMaxNodes  = 1 << 20 // the solver gives up after so many positions
+	endgameCheckTime = 1 << 10 // positions between the checks of the deadline
+)
+
+// The solver searches all orders of claiming the last free rivers by all
+// punters in turn, each punter maximizing its own score (max^n). The
+// positions are memoized by the rivers every punter has claimed.
+type endgameSolver struct {
+	g       *Graph
+	punters int
+	me      int
+	futures []Future // of the punter to move, the others' are unknown
+
+	free      []int       // edge ids of the free rivers
+	comp      [][]int     // comp[p][v] is the component of v over the rivers of punter p
+	mineScore [][][]int64 // mineScore[p][i][c] is the score of component c for mine i
+	memo      map[string][]int64
+	owner     []int // owner[r] of the free river r during the search, -1 if free
+
+	nodes    int
+	deadline deadline
+	aborted  bool
+}
+
+func newEndgameSolver(g *Graph, punters, me int, futures []Future, d deadline) *endgameSolver {
+	s := &endgameSolver{g: g, punters: punters, me: me, futures: futures, deadline: d}
+	for _, e := range g.AllEdges {
+		if e.Owner < 0 && e.Src < e.Dst {
+			s.free = append(s.free, e.Id)
+		}
+	}
+	s.owner = make([]int, len(s.free))
+	for r := range s.owner {
+		s.owner[r] = -1
+	}
+
+	s.comp = make([][]int, punters)
+	s.mineScore = make([][][]int64, punters)
+	for p := 0; p < punters; p++ {
+		scorer := MakeScorer(g, p, nil)
+		s.comp[p] = scorer.comp
+		s.mineScore[p] = scorer.mineScore
+	}
+	s.memo = make(map[string][]int64)
+	return s
+}
+
+// Returns the score of punter p when it gets the free rivers it owns in
+// the search: the components they join are merged.
+func (s *endgameSolver) score(p int) (score int64) {
+	comp := s.comp[p]
+	parent := make(map[int]int)
+	var find func(c int) int
+	find = func(c int) int {
+		pc, ok := parent[c]
+		if !ok || pc == c {
+			return c
+		}
+		parent[c] = find(pc)
+		return parent[c]
+	}
+	for r, o := range s.owner {
+		if o != p {
+			continue
+		}
+		e := &s.g.AllEdges[s.free[r]]
+		a, b := find(comp[e.Src]), find(comp[e.Dst])
+		parent[a], parent[b] = a, a
+	}
+
+	for i, m := range s.g.Mines {
+		root := find(comp[m])
+		if _, ok := parent[comp[m]]; !ok {
+			score += s.mineScore[p][i][comp[m]]
+			continue
+		}
+		for c := range parent {
+			if find(c) == root {
+				score += s.mineScore[p][i][c]
+			}
+		}
+	}
+
+	if p == s.me {
+		for _, f := range s.futures {
+			i := s.g.MineIndex(f.Src)
+			if i < 0 || s.g.Distance[i][f.Dst] < 0 {
+				continue
+			}
+			d := int64(s.g.Distance[i][f.Dst])
+			if find(comp[f.Src]) == find(comp[f.Dst]) {
+				score += d * d * d
+			} else {
+				score -= d * d * d
+			}
+		}
+	}
+	return
+}
+
+// The key of the position: the bitset of the rivers of every punter.
+func (s *endgameSolver) key() string {
+	words := (len(s.free) + 63) / 64
+	bs := make([]byte, 8*words*s.punters)
+	for r, o := range s.owner {
+		if o >= 0 {
+			w := o*words + r/64
+			v := binary.LittleEndian.Uint64(bs[8*w:])
+			binary.LittleEndian.PutUint64(bs[8*w:], v|1<<uint(r%64))
+		}
+	}
+	return string(bs)
+}
+
+// Returns the final scores of all punters when the punters claim the
+// remaining rivers in turn after the moved ones, and the best river for
+// the punter to move, -1 if the search was aborted.
+func (s *endgameSolver) solve(moved int) (values []int64, best int) {
+	s.nodes++
+	if s.nodes > endgameMaxNodes || (s.nodes%endgameCheckTime == 0 && s.deadline.passed()) {
+		s.aborted = true
+	}
+	if s.aborted {
+		return nil, -1
+	}
+
+	if moved == len(s.free) {
+		values = make([]int64, s.punters)
+		for p := range values {
+			values[p] = s.score(p)
+		}
+		return values, -1
+	}
+
+	key := ""
+	if moved > 0 {
+		key = s.key()
+		if v, ok := s.memo[key]; ok {
+			return v, -1
+		}
+	}
+
+	p := (s.me + moved) % s.punters
+	best = -1
+	var others int64
+	for r := range s.free {
+		if s.owner[r] >= 0 {
+			continue
+		}
+		s.owner[r] = p
+		v, _ := s.solve(moved + 1)
+		s.owner[r] = -1
+		if s.aborted {
+			return nil, -1
+		}
+
+		// Ties go to the move that leaves less to the others.
+		var o int64
+		for q, vq := range v {
+			if q != p {
+				o += vq
+			}
+		}
+		if best < 0 || v[p] > values[p] || (v[p] == values[p] && o < others) {
+			values, best, others = v, r, o
+		}
+	}
+	if moved > 0 {
+		s.memo[key] = values
+	}
+	return values, best
+}
+
+// Solves the endgame when at most maxFree free rivers are left. Returns
+// the river to claim and false if there are more free rivers or the
+// search doesn't fit in the deadline.
+func (p *BaselinePlayer) SolveEndgame(maxFree int, d deadline) (u, v int, ok bool) {
+	free := 0
+	for _, e := range p.AllEdges {
+		if e.Owner < 0 {
+			free++
+		}
+	}
+	if free == 0 || free/2 > maxFree {
+		return
+	}
+
+	s := newEndgameSolver(&p.Graph, p.Punters, p.Punter, p.Futures, d)
+	_, best := s.solve(0)
+	if best < 0 {
+		return
+	}
+	e := &p.AllEdges[s.free[best]]
+	return e.Src, e.Dst, true
+}
+
+func (p *BaselinePlayer) baseline() *BaselinePlayer { return p }
+
+// Bots that embed the BaselinePlayer.
+type baselineBased interface {
+	Player
+	baseline() *BaselinePlayer
+}
+
+// EndgamePlayer adds the endgame solver to a bot based on the baseline:
+// when few free rivers are left, it claims the best river for the rest
+// of the game, otherwise the bot moves.
+type EndgamePlayer struct {
+	Bot      Player `json:"bot"`
+	Rivers   int    `json:"rivers"` // the solver starts with this many free rivers
+	deadline deadline
+}
+
+// Returns the endgame player for the bot, nil if the bot doesn't embed
+// the BaselinePlayer.
+func makeEndgamePlayer(bot Player) *EndgamePlayer {
+	if _, ok := bot.(baselineBased); !ok {
+		return nil
+	}
+	return &EndgamePlayer{Bot: bot}
+}
+
+func (p *EndgamePlayer) Setup(punter, punters int, m Map, s Settings) {
+	p.Bot.Setup(punter, punters, m, s)
+}
+
+func (p *EndgamePlayer) MakeMove(moves []Move) Move {
+	b := p.Bot.(baselineBased).baseline()
+	b.ApplyMoves(moves)
+	if u, v, ok := b.SolveEndgame(p.Rivers, p.deadline); ok {
+		return b.MakeClaimMove(u, v)
+	}
+	return p.Bot.MakeMove(moves)
+}
+
+func (p *EndgamePlayer) Name() string { return EndgamePrefix + p.Bot.Name() }
+
+func (p *EndgamePlayer) GetPunter() int { return p.Bot.GetPunter() }
+
+func (p *EndgamePlayer) GetFutures() []Future { return p.Bot.GetFutures() }
+
+func (p *EndgamePlayer) SetFutures(futures []Future) {
+	p.Bot.(baselineBased).baseline().SetFutures(futures)
+}
+
+func (p *EndgamePlayer) Params() []Param {
+	return append(PlayerParams(p.Bot), Param{Name: "endgameRivers", Default: 10, Doc: "the solver starts with this many free rivers", Min: 0, Max: 20, Integer: true})
+}
+
+func (p *EndgamePlayer) SetParam(name string, value float64) {
+	if name == "endgameRivers" {
+		p.Rivers = int(value)
+	} else if t, ok := p.Bot.(Tunable); ok {
+		t.SetParam(name, value)
+	}
+}
+
+func (p *EndgamePlayer) SetTimeBudget(budget time.Duration) {
+	p.deadline = deadline(time.Now().Add(budget))
+	if t, ok := p.Bot.(TimeLimited); ok {
+		t.SetTimeBudget(budget)
+	}
+}
+
+// Downgrades the bot and keeps the solver, or drops the solver if the
+// bot can't be downgraded.
+func (p *EndgamePlayer) Downgrade() Player {
+	d, ok := p.Bot.(Downgradable)
+	if !ok {
+		return p.Bot
+	}
+	bot := d.Downgrade()
+	if e := makeEndgamePlayer(bot); e != nil {
+		e.Rivers = p.Rivers
+		return e
+	}
+	return bot
+}
+
+func newEndgamePlayer(name string) Player {
+	if !strings.HasPrefix(name, EndgamePrefix) {
+		return nil
+	}
+	bot := newPlayer(name[len(EndgamePrefix):])
+	if bot == nil {
+		return nil
+	}
+	if e := makeEndgamePlayer(bot); e != nil {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestEndgameScore(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+	g.SetEdgeOwnership(0, 1, 0)
+	g.SetEdgeOwnership(4, 5, 1)
+	futures := []Future{{Src: 0, Dst: 3}, {Src: 4, Dst: 6}}
+
+	r := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 50; iter++ {
+		s := newEndgameSolver(&g, 2, 0, futures, deadline{})
+		claimed := g
+		claimed.AllEdges = append([]Edge(nil), g.AllEdges...)
+		for i, eId := range s.free {
+			s.owner[i] = r.Intn(3) - 1
+			if s.owner[i] >= 0 {
+				e := &claimed.AllEdges[eId]
+				claimed.SetEdgeOwnership(e.Src, e.Dst, s.owner[i])
+			}
+		}
+		for p := 0; p < 2; p++ {
+			var fs []Future
+			if p == 0 {
+				fs = futures
+			}
+			scorer := MakeScorer(&claimed, p, fs)
+			if got, want := s.score(p), scorer.Score(); got != want {
+				t.Errorf("owners %v, punter %v: got %v, want %v", s.owner, p, got, want)
+			}
+		}
+	}
+}
+
+func TestSolveEndgame(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	p.ApplyMoves([]Move{
+		MakeClaimMove(0, 0, 1), MakeClaimMove(1, 4, 5),
+		MakeClaimMove(0, 1, 2), MakeClaimMove(1, 5, 6),
+	})
+
+	// Site 3 is worth 9, river (4, 6) only 2.
+	if u, v, ok := p.SolveEndgame(2, deadline{}); !ok || u != 2 || v != 3 {
+		t.Errorf("got (%v, %v, %v), want (2, 3)", u, v, ok)
+	}
+	if _, _, ok := p.SolveEndgame(1, deadline{}); ok {
+		t.Error("solved with more free rivers than the limit")
+	}
+}
+
+func TestSolveEndgameFuture(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{FuturesMode: true})
+	p.SetFutures([]Future{{Src: 0, Dst: 2}})
+	p.ApplyMoves([]Move{
+		MakeClaimMove(0, 0, 1), MakeClaimMove(1, 2, 3),
+		MakeClaimMove(0, 4, 5), MakeClaimMove(1, 5, 6),
+	})
+
+	// River (1, 2) is worth 4 and turns the future from -8 to +8, river
+	// (4, 6) is worth 3.
+	u, v, ok := p.SolveEndgame(10, deadline{})
+	if !ok || u != 1 || v != 2 {
+		t.Errorf("got (%v, %v, %v), want (1, 2)", u, v, ok)
+	}
+}
+
+func TestEndgamePlayer(t *testing.T) {
+	p, err := MakePlayerWithParams(EndgamePrefix+"random1", map[string]float64{"depth": 3, "endgameRivers": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := p.(*EndgamePlayer)
+	if e.Rivers != 4 || e.Bot.(*Random1Player).Depth != 3 || p.Name() != "endgame:random1" {
+		t.Errorf("got %+v", e)
+	}
+	if d, ok := e.Downgrade().(*EndgamePlayer); !ok || d.Rivers != 4 || d.Bot.Name() != "baseline" {
+		t.Errorf("downgrade: got %v", e.Downgrade())
+	}
+	if _, err := MakePlayerWithParams(EndgamePrefix+"zombie", nil); err == nil {
+		t.Error("endgame zombie: no error")
+	}
+}
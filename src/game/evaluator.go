@@ -0,0 +1,221 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Evaluator scores the free rivers for the punter to move, higher is
+// better. Prepare is called after PrepareForMove, before the rivers are
+// scored, so that the work shared by all rivers is done once.
+type Evaluator interface {
+	Prepare(p *BaselinePlayer)
+	Score(p *BaselinePlayer, e *Edge) float64
+}
+
+// Returns the evaluator with the given name, see evaluatorNames.
+func newEvaluator(name string) (Evaluator, error) {
+	switch name {
+	case "gain":
+		return new(gainEvaluator), nil
+	case "bonus":
+		return new(bonusEvaluator), nil
+	case "freedom":
+		return new(freedomEvaluator), nil
+	case "mine":
+		return new(mineEvaluator), nil
+	case "territory":
+		return new(territoryEvaluator), nil
+	case "defense":
+		return new(defenseEvaluator), nil
+	}
+	return nil, fmt.Errorf("unknown evaluator: %v", name)
+}
+
+var evaluatorNames = []string{"gain", "bonus", "freedom", "mine", "territory", "defense"}
+
+// Term is an evaluator with its weight in a combination.
+type Term struct {
+	Evaluator string
+	Weight    float64
+}
+
+// Weighted is the sum of the scores of evaluators times their weights.
+// Evaluators with zero weight are skipped.
+type Weighted struct {
+	evaluators []Evaluator
+	weights    []float64
+}
+
+func MakeWeighted(terms []Term) (w Weighted, err error) {
+	for _, t := range terms {
+		if t.Weight == 0 {
+			continue
+		}
+		e, err := newEvaluator(t.Evaluator)
+		if err != nil {
+			return w, err
+		}
+		w.evaluators = append(w.evaluators, e)
+		w.weights = append(w.weights, t.Weight)
+	}
+	return w, nil
+}
+
+func (w *Weighted) Prepare(p *BaselinePlayer) {
+	for _, e := range w.evaluators {
+		e.Prepare(p)
+	}
+}
+
+func (w *Weighted) Score(p *BaselinePlayer, e *Edge) (score float64) {
+	for i, ev := range w.evaluators {
+		score += w.weights[i] * ev.Score(p, e)
+	}
+	return
+}
+
+// Returns the free rivers touching the punter's network, or all free
+// rivers if there are none, one direction of each.
+func (p *BaselinePlayer) FreeRiversNearby() []*Edge {
+	own := p.network(p.Punter)
+	var near, all []*Edge
+	for i := 0; i < len(p.AllEdges); i += 2 {
+		e := &p.AllEdges[i]
+		if e.Owner >= 0 {
+			continue
+		}
+		all = append(all, e)
+		if own[e.Src] || own[e.Dst] {
+			near = append(near, e)
+		}
+	}
+	if len(near) == 0 {
+		return all
+	}
+	return near
+}
+
+// The score increase of FindEdge: the gain of the claim and the progress
+// towards the futures.
+type gainEvaluator struct{}
+
+func (*gainEvaluator) Prepare(p *BaselinePlayer) {}
+
+func (*gainEvaluator) Score(p *BaselinePlayer, e *Edge) float64 {
+	return float64(p.scorer.ClaimGain(e.Src, e.Dst) + p.FutureProgress(e.Src, e.Dst))
+}
+
+// The score of the free neighbours of the site the river adds to a mine,
+// i.e. what the next claim from there could bring.
+type bonusEvaluator struct{}
+
+func (*bonusEvaluator) Prepare(p *BaselinePlayer) {}
+
+func (*bonusEvaluator) Score(p *BaselinePlayer, e *Edge) (bonus float64) {
+	for i := range p.Mines {
+		rS, rD := p.reachableFromMine[i][e.Src], p.reachableFromMine[i][e.Dst]
+		if rS == rD {
+			continue
+		}
+		u, v := e.Dst, e.Src
+		if rD {
+			u, v = e.Src, e.Dst
+		}
+		for _, eId := range p.Edges[u] {
+			next := &p.AllEdges[eId]
+			if next.Owner >= 0 || next.Dst == v || p.reachableFromMine[i][next.Dst] {
+				continue
+			}
+			bonus += float64(p.SiteScore(i, next.Dst))
+		}
+	}
+	return
+}
+
+// The number of free rivers at the ends of the river that are not
+// connected to a mine yet, i.e. the room to grow from there.
+type freedomEvaluator struct {
+	reachable []bool
+}
+
+func (f *freedomEvaluator) Prepare(p *BaselinePlayer) {
+	f.reachable = make([]bool, p.NumSites)
+	for i := range p.Mines {
+		for v, r := range p.reachableFromMine[i] {
+			f.reachable[v] = f.reachable[v] || r
+		}
+	}
+}
+
+func (f *freedomEvaluator) Score(p *BaselinePlayer, e *Edge) float64 {
+	return float64(p.freeRiversAround(e, f.reachable))
+}
+
+// One for the rivers at a mine.
+type mineEvaluator struct{}
+
+func (*mineEvaluator) Prepare(p *BaselinePlayer) {}
+
+func (*mineEvaluator) Score(p *BaselinePlayer, e *Edge) float64 {
+	if p.MineIndex(e.Src) >= 0 || p.MineIndex(e.Dst) >= 0 {
+		return 1
+	}
+	return 0
+}
+
+// The Territory margin of the punter after the claim. It computes the
+// territory for every river, so it is slow on large maps.
+type territoryEvaluator struct{}
+
+func (*territoryEvaluator) Prepare(p *BaselinePlayer) {}
+
+func (*territoryEvaluator) Score(p *BaselinePlayer, e *Edge) float64 {
+	e.Owner, p.AllEdges[e.Id^1].Owner = p.Punter, p.Punter
+	t := MakeTerritory(&p.Graph, p.Punters)
+	e.Owner, p.AllEdges[e.Id^1].Owner = -1, -1
+	return float64(t.Margin(p.Punter))
+}
+
+const defenseMaxCut = 3 // the cuts larger than this are safe enough
+
+// The value of the futures and mine links the river helps to defend,
+// divided by the number of rivers the others need to cut them.
+type defenseEvaluator struct {
+	value map[int]float64 // by the river, i.e. the edge id / 2
+}
+
+func (d *defenseEvaluator) Prepare(p *BaselinePlayer) {
+	d.value = make(map[int]float64)
+	for _, v := range p.Vulnerabilities(defenseMaxCut + 1) {
+		for _, eId := range v.Rivers {
+			d.value[eId>>1] += float64(v.Value) / float64(v.Cut)
+		}
+	}
+}
+
+func (d *defenseEvaluator) Score(p *BaselinePlayer, e *Edge) float64 {
+	return d.value[e.Id>>1]
+}
+
+// Scores the rivers with the evaluator, the best first.
+func rankRivers(p *BaselinePlayer, ev Evaluator, rivers []*Edge) []float64 {
+	scores := make([]float64, len(rivers))
+	for i, e := range rivers {
+		scores[i] = ev.Score(p, e)
+	}
+	sort.Stable(byScore{rivers, scores})
+	return scores
+}
+
+type byScore struct {
+	rivers []*Edge
+	scores []float64
+}
+
+func (b byScore) Len() int           { return len(b.rivers) }
+func (b byScore) Less(i, j int) bool { return b.scores[i] > b.scores[j] }
+func (b byScore) Swap(i, j int) {
+	b.rivers[i], b.rivers[j] = b.rivers[j], b.rivers[i]
+	b.scores[i], b.scores[j] = b.scores[j], b.scores[i]
+}
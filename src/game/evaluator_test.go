@@ -0,0 +1,111 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestEvaluators(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	p.PrepareForMove([]Move{{Type: Claim, Punter: 0, Source: 0, Target: 1}})
+
+	// The river (1, 2) brings site 2 to mine 0, site 3 is next.
+	e := &p.AllEdges[2]
+	tests := []struct {
+		name string
+		want float64
+	}{
+		{"gain", 4},
+		{"bonus", 9},
+		{"freedom", 1},
+		{"mine", 0},
+		{"territory", 14 - 4},
+		{"defense", 0},
+	}
+	for _, test := range tests {
+		ev, err := newEvaluator(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ev.Prepare(&p)
+		if got := ev.Score(&p, e); got != test.want {
+			t.Errorf("%v: got %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	w, err := MakeWeighted([]Term{{"gain", 2}, {"bonus", 1}, {"territory", 0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Prepare(&p)
+	if got := w.Score(&p, e); got != 2*4+9 {
+		t.Errorf("weighted: got %v, want %v", got, 2*4+9)
+	}
+	if _, err := MakeWeighted([]Term{{"nonsense", 1}}); err == nil {
+		t.Error("unknown evaluator: no error")
+	}
+}
+
+func TestSelectors(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	p.PrepareForMove([]Move{{Type: Claim, Punter: 0, Source: 0, Target: 1}})
+	ev, _ := MakeWeighted([]Term{{"gain", 1}})
+	ev.Prepare(&p)
+	rivers := p.FreeRiversNearby()
+
+	for _, name := range []string{"argmax", "softmax", "topk"} {
+		values := map[string]float64{"temperature": 0.1, "k": 2, "discount": 1}
+		sel, err := newSelector(name, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := sel.Select(&p, &ev, rivers, rand.New(rand.NewSource(42)))
+		if e == nil || e.Src != 1 || e.Dst != 2 {
+			t.Errorf("%v: got %+v, want the river (1, 2)", name, e)
+		}
+	}
+	// The lookahead leaves the position as it was.
+	if got := p.AllEdges[4].Owner; got != -1 {
+		t.Errorf("topk: the river (2, 3) is owned by %v after the lookahead", got)
+	}
+}
+
+func TestCompositions(t *testing.T) {
+	for _, name := range CompositionNames() {
+		p, err := MakePlayerWithParams(name, map[string]float64{"gain": 2, "territory": 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Name() != name {
+			t.Errorf("name: got %v, want %v", p.Name(), name)
+		}
+		// Every evaluator has a weight, zero if the composition doesn't use it.
+		weights := make(map[string]float64)
+		for _, param := range PlayerParams(p) {
+			weights[param.Name] = param.Default
+		}
+		for _, ev := range evaluatorNames {
+			if w, ok := weights[ev]; !ok || ev == "territory" && w != 0 {
+				t.Errorf("%v: weight of %v: got %v, %v", name, ev, w, ok)
+			}
+		}
+		p.Setup(0, 2, disconnectedMap(), Settings{})
+		p.MakeMove(nil)
+
+		// The weights survive the offline state.
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := newPlayer(name).(*ComposedPlayer)
+		if err := json.Unmarshal(data, q); err != nil {
+			t.Fatal(err)
+		}
+		if q.Values["gain"] != 2 || q.Values["territory"] != 1 || q.Values["seed"] != 42 {
+			t.Errorf("%v: values after the state round trip: %v", name, q.Values)
+		}
+	}
+}
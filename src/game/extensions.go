@@ -3,6 +3,7 @@ package game
 type Settings struct {
 	FuturesMode  bool `json:"futures,omitempty"`
 	SplurgesMode bool `json:"splurges,omitempty"`
+	OptionsMode  bool `json:"options,omitempty"`
 }
 
 func (s *Settings) String() (str string) {
@@ -12,6 +13,12 @@ func (s *Settings) String() (str string) {
 	if s.SplurgesMode {
 		str += " Splurges"
 	}
+	if s.OptionsMode {
+		str += " Options"
+	}
+	if str == "" {
+		return "None"
+	}
 	return str[1:]
 }
 
@@ -0,0 +1,112 @@
+package game
+
+import "math"
+
+// FeatureNames are the features of claiming a river, in the order of
+// Features.Of.
+var FeatureNames = []string{"gain", "freedom", "mine", "frontier", "future"}
+
+// Features describes the claims of the punter to move in a position. Gain,
+// freedom and future are shares of their maximum over FreeRiversNearby,
+// so that the features are comparable across maps and stages of the game.
+//
+//	gain      the squared distances the claim adds to the score
+//	freedom   the free rivers at the sites not connected to a mine yet
+//	mine      1/(1+d) for the distance d from the river to the nearest mine
+//	frontier  the share of the others whose rivers touch the river's sites
+//	future    the progress towards the futures
+type Features struct {
+	p         *BaselinePlayer
+	max       [3]float64 // the maximum gain, freedom and future, at least 1
+	mineDist  []int      // mineDist[v] is the distance from site v to the nearest mine, or -1
+	touched   [][]bool   // touched[q][v] if a river of punter q touches site v
+	reachable []bool     // reachable[v] if site v is connected to a mine by the punter's rivers
+}
+
+// Prepares the features for the position after PrepareForMove.
+func MakeFeatures(p *BaselinePlayer) (f Features) {
+	f.p = p
+	f.mineDist = make([]int, p.NumSites)
+	f.reachable = make([]bool, p.NumSites)
+	for v := range f.mineDist {
+		f.mineDist[v] = -1
+		for i := range p.Mines {
+			if d := p.Distance[i][v]; d >= 0 && (f.mineDist[v] < 0 || d < f.mineDist[v]) {
+				f.mineDist[v] = d
+			}
+			f.reachable[v] = f.reachable[v] || p.reachableFromMine[i][v]
+		}
+	}
+
+	f.touched = make([][]bool, p.Punters)
+	for q := range f.touched {
+		f.touched[q] = make([]bool, p.NumSites)
+	}
+	for _, e := range p.AllEdges {
+		if e.Owner >= 0 {
+			f.touched[e.Owner][e.Src] = true
+		}
+	}
+
+	f.max = [3]float64{1, 1, 1}
+	for _, e := range p.FreeRiversNearby() {
+		for i, v := range f.raw(e) {
+			f.max[i] = math.Max(f.max[i], v)
+		}
+	}
+	return
+}
+
+// Returns the gain, freedom and future of the claim.
+func (f *Features) raw(e *Edge) [3]float64 {
+	p := f.p
+	return [3]float64{
+		float64(p.scorer.ClaimGain(e.Src, e.Dst)),
+		float64(p.freeRiversAround(e, f.reachable)),
+		float64(p.FutureProgress(e.Src, e.Dst)),
+	}
+}
+
+// Returns the features of claiming the free river e.
+func (f *Features) Of(e *Edge) []float64 {
+	p := f.p
+	x := make([]float64, len(FeatureNames))
+	raw := f.raw(e)
+	x[0] = raw[0] / f.max[0]
+	x[1] = raw[1] / f.max[1]
+	x[4] = raw[2] / f.max[2]
+
+	d := f.mineDist[e.Src]
+	if dd := f.mineDist[e.Dst]; d < 0 || dd >= 0 && dd < d {
+		d = dd
+	}
+	if d >= 0 {
+		x[2] = 1 / float64(1+d)
+	}
+
+	if p.Punters > 1 {
+		for q, t := range f.touched {
+			if q != p.Punter && (t[e.Src] || t[e.Dst]) {
+				x[3]++
+			}
+		}
+		x[3] /= float64(p.Punters - 1)
+	}
+	return x
+}
+
+// Returns the number of free rivers other than e at the sites of e that
+// are not reachable.
+func (g *Graph) freeRiversAround(e *Edge, reachable []bool) (n int) {
+	for _, u := range []int{e.Src, e.Dst} {
+		if reachable[u] {
+			continue
+		}
+		for _, eId := range g.Edges[u] {
+			if next := &g.AllEdges[eId]; next.Owner < 0 && next.Id>>1 != e.Id>>1 {
+				n++
+			}
+		}
+	}
+	return
+}
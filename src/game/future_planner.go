@@ -0,0 +1,182 @@
+package game
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	futureBudgetShare   = 0.5 // share of our rivers we are ready to spend on futures
+	futureMaxPaths      = 3   // edge-disjoint paths are counted up to this number
+	futureSitesPerDist  = 3   // candidate targets per mine and distance
+	futureCorridorScale = 4.0 // how much more likely opponents claim contested rivers
+)
+
+// FutureEstimate is the estimated outcome of betting on a future.
+type FutureEstimate struct {
+	Future
+	Dist       int     // distance from the mine to the target
+	Paths      int     // edge-disjoint paths from the mine to the target, up to futureMaxPaths
+	Contention float64 // average share of mine pairs whose shortest paths use the rivers on the way
+	Chance     float64 // estimated probability of completing the future
+	Value      float64 // expected payoff: Chance*d^3 - (1-Chance)*d^3
+}
+
+// FuturePlanner picks the futures that maximize the expected payoff given
+// the number of punters, the rivers we will get, how many ways there are
+// to the target and how contested these ways are.
+type FuturePlanner struct {
+	g        *Graph
+	punters  int
+	corridor []float64 // corridor[r] is the share of mine pairs with a shortest path over river r
+
+	BudgetShare float64 // share of our rivers we are ready to spend on futures
+}
+
+func MakeFuturePlanner(g *Graph, punters int) (fp FuturePlanner) {
+	fp.g = g
+	fp.punters = punters
+	fp.BudgetShare = futureBudgetShare
+	fp.corridor = make([]float64, len(g.AllEdges)/2)
+
+	pairs := 0
+	for i := range g.Mines {
+		for j := i + 1; j < len(g.Mines); j++ {
+			dist := g.Distance[i][g.Mines[j]]
+			if dist < 0 {
+				continue
+			}
+			pairs++
+			for _, e := range g.AllEdges {
+				di, dj := g.Distance[i][e.Src], g.Distance[j][e.Dst]
+				if di >= 0 && dj >= 0 && di+1+dj == dist {
+					fp.corridor[e.Id/2]++
+				}
+			}
+		}
+	}
+	if pairs > 0 {
+		for r := range fp.corridor {
+			fp.corridor[r] /= float64(pairs)
+		}
+	}
+	return
+}
+
+// Returns the number of rivers the punter is expected to claim during the game.
+func (fp *FuturePlanner) RiversPerPunter() float64 {
+	return float64(len(fp.g.AllEdges)/2) / float64(fp.punters)
+}
+
+// Returns the estimate for the future from the i-th mine to site v,
+// without the chance and value that depend on the budget.
+func (fp *FuturePlanner) Estimate(i, v int) (fe FutureEstimate) {
+	g := fp.g
+	fe.Src = g.Mines[i]
+	fe.Dst = v
+	fe.Dist = g.Distance[i][v]
+	fe.Paths = g.DisjointPaths(fe.Src, v, futureMaxPaths)
+
+	// Walk back along the least contested shortest path.
+	for u := v; u != fe.Src; {
+		bestE, bestC := -1, 0.0
+		for _, eId := range g.Edges[u] {
+			e := &g.AllEdges[eId]
+			if g.Distance[i][e.Dst] != g.Distance[i][u]-1 {
+				continue
+			}
+			if c := fp.corridor[eId/2]; bestE < 0 || c < bestC {
+				bestE, bestC = eId, c
+			}
+		}
+		fe.Contention += bestC
+		u = g.AllEdges[bestE].Dst
+	}
+	fe.Contention /= float64(fe.Dist)
+	return
+}
+
+// Computes the chance and the value of the future given that budget
+// rivers are left for futures.
+func (fp *FuturePlanner) Evaluate(fe *FutureEstimate, budget float64) {
+	d := float64(fe.Dist)
+	d3 := d * d * d
+
+	pBudget := 0.0
+	if d < budget {
+		pBudget = 1 - (d/budget)*(d/budget)
+	}
+
+	// While we spend d turns on the future, the opponents claim
+	// (punters-1)*d rivers; on average a river on the path is exposed
+	// for half of this time.
+	free := float64(len(fp.g.AllEdges) / 2)
+	q := float64(fp.punters-1) * d / free / 2 * (1 + futureCorridorScale*fe.Contention)
+	if q > 1 {
+		q = 1
+	}
+	survive := math.Pow(1-q, d)
+	pPath := 1 - math.Pow(1-survive, float64(fe.Paths))
+
+	fe.Chance = pBudget * pPath
+	fe.Value = fe.Chance*d3 - (1-fe.Chance)*d3
+}
+
+// Returns the candidate targets for the i-th mine: a few sites with
+// the largest degree at every distance.
+func (fp *FuturePlanner) candidates(i int, allowed func(i, v int) bool) (cs []int) {
+	g := fp.g
+	byDist := make(map[int][]int)
+	for v, d := range g.Distance[i] {
+		if d > 0 && (allowed == nil || allowed(i, v)) {
+			byDist[d] = append(byDist[d], v)
+		}
+	}
+	for _, vs := range byDist {
+		sort.Slice(vs, func(a, b int) bool {
+			if len(g.Edges[vs[a]]) != len(g.Edges[vs[b]]) {
+				return len(g.Edges[vs[a]]) > len(g.Edges[vs[b]])
+			}
+			return vs[a] < vs[b]
+		})
+		if len(vs) > futureSitesPerDist {
+			vs = vs[:futureSitesPerDist]
+		}
+		cs = append(cs, vs...)
+	}
+	sort.Ints(cs)
+	return
+}
+
+// Returns at most one future per mine. The futures are chosen greedily
+// by the expected payoff, and every chosen future reduces the budget for
+// the next ones. Mines for which no future has a positive expected
+// payoff get no future. If allowed is not nil, only the targets it
+// accepts are considered.
+func (fp *FuturePlanner) ChooseFutures(allowed func(i, v int) bool) (chosen []FutureEstimate) {
+	estimates := make([][]FutureEstimate, len(fp.g.Mines))
+	for i := range fp.g.Mines {
+		for _, v := range fp.candidates(i, allowed) {
+			estimates[i] = append(estimates[i], fp.Estimate(i, v))
+		}
+	}
+
+	budget := fp.BudgetShare * fp.RiversPerPunter()
+	for {
+		bestI, best := -1, FutureEstimate{}
+		for i := range estimates {
+			for _, fe := range estimates[i] {
+				fp.Evaluate(&fe, budget)
+				if fe.Value > 0 && (bestI < 0 || fe.Value > best.Value) {
+					bestI, best = i, fe
+				}
+			}
+		}
+		if bestI < 0 {
+			return
+		}
+		chosen = append(chosen, best)
+		estimates[bestI] = nil
+		budget -= float64(best.Dist)
+	}
+}
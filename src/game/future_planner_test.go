@@ -0,0 +1,38 @@
+package game
+
+import "testing"
+
+func TestDisjointPaths(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+
+	tests := []struct{ s, t, limit, want int }{
+		{0, 3, 3, 1},
+		{4, 6, 3, 2},
+		{4, 6, 1, 1},
+		{0, 4, 3, 0},
+	}
+	for _, test := range tests {
+		if got := g.DisjointPaths(test.s, test.t, test.limit); got != test.want {
+			t.Errorf("paths from %v to %v up to %v: got %v, want %v", test.s, test.t, test.limit, got, test.want)
+		}
+	}
+}
+
+func TestChooseFutures(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+
+	// Alone on the map we get all 6 rivers, half of them may go to futures.
+	fp := MakeFuturePlanner(&g, 1)
+	fs := fp.ChooseFutures(nil)
+	if len(fs) != 1 || fs[0].Future != (Future{Src: 0, Dst: 2}) {
+		t.Errorf("futures for a single punter: got %v, want [{0 2}]", fs)
+	}
+
+	// Too many punters for too few rivers.
+	fp = MakeFuturePlanner(&g, 10)
+	if fs := fp.ChooseFutures(nil); len(fs) != 0 {
+		t.Errorf("futures for 10 punters: got %v, want none", fs)
+	}
+}
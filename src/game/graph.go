@@ -1,10 +1,16 @@
 package game
 
 type Edge struct {
-	Id    int `json:"id"`
-	Src   int `json:"src"`
-	Dst   int `json:"dst"`
-	Owner int `json:"owner"`
+	Id     int `json:"id"`
+	Src    int `json:"src"`
+	Dst    int `json:"dst"`
+	Owner  int `json:"owner"`
+	Option int `json:"option"` // the punter holding an option on the river, or -1
+}
+
+// Returns true if the punter can use the river for scoring.
+func (e *Edge) UsableBy(punter int) bool {
+	return e.Owner == punter || e.Option == punter
 }
 
 type Graph struct {
@@ -12,7 +18,17 @@ type Graph struct {
 	AllEdges []Edge  `json:"allEdges"`
 	Edges    [][]int `json:"edges"`
 	Mines    []int   `json:"mines"`    // indexes of mines
-	Distance [][]int `json:"distance"` // distance[i][j] = shortest distance from mine i to site j
+	Distance [][]int `json:"distance"` // distance[i][j] = shortest distance from mine i to site j, or -1
+}
+
+// Returns the score for connecting site v to mine i, i.e. the squared
+// distance. Sites unreachable from the mine are worth nothing.
+func (g *Graph) SiteScore(i, v int) int64 {
+	d := int64(g.Distance[i][v])
+	if d < 0 {
+		return 0
+	}
+	return d * d
 }
 
 func (g *Graph) InitGraph(m Map) {
@@ -24,8 +40,8 @@ func (g *Graph) InitGraph(m Map) {
 		a := r.Source
 		b := r.Target
 
-		g.AllEdges[2*i] = Edge{Id: 2 * i, Src: a, Dst: b, Owner: -1}
-		g.AllEdges[2*i+1] = Edge{Id: 2*i + 1, Src: b, Dst: a, Owner: -1}
+		g.AllEdges[2*i] = Edge{Id: 2 * i, Src: a, Dst: b, Owner: -1, Option: -1}
+		g.AllEdges[2*i+1] = Edge{Id: 2*i + 1, Src: b, Dst: a, Owner: -1, Option: -1}
 		g.Edges[a] = append(g.Edges[a], 2*i)
 		g.Edges[b] = append(g.Edges[b], 2*i+1)
 	}
@@ -33,6 +49,43 @@ func (g *Graph) InitGraph(m Map) {
 	g.initShortestPaths()
 }
 
+func (g *Graph) SetEdgeOwnership(a, b, owner int) {
+	for _, eId := range g.Edges[a] {
+		e := &g.AllEdges[eId]
+		if e.Dst == b {
+			if e.Owner >= 0 && e.Owner != owner {
+				panic("a previously claimed edge was claimed in a non-pass move")
+			}
+			e.Owner = owner
+			g.AllEdges[e.Id^1].Owner = owner
+		}
+	}
+}
+
+// Gives the punter an option on the river (a, b) claimed by someone else.
+func (g *Graph) SetEdgeOption(a, b, punter int) {
+	for _, eId := range g.Edges[a] {
+		e := &g.AllEdges[eId]
+		if e.Dst == b {
+			if e.Option >= 0 && e.Option != punter {
+				panic("an option on the edge was bought twice")
+			}
+			e.Option = punter
+			g.AllEdges[e.Id^1].Option = punter
+		}
+	}
+}
+
+// Returns the index of the mine at site v, or -1 if v is not a mine.
+func (g *Graph) MineIndex(v int) int {
+	for i, m := range g.Mines {
+		if m == v {
+			return i
+		}
+	}
+	return -1
+}
+
 func (g *Graph) initShortestPaths() {
 	g.Distance = make([][]int, len(g.Mines))
 	for i := range g.Distance {
@@ -41,31 +94,36 @@ func (g *Graph) initShortestPaths() {
 }
 
 func (g *Graph) SSSP(s int) []int {
-	n := len(g.Edges)
-	d := make([]int, n)
-	for i := range d {
-		d[i] = -1
-	}
-	d[s] = 0
-	q := make([]int, n)
-	qt, qh := 0, 1
-	q[0] = s
-	for qt < qh {
-		v := q[qt]
-		qt++
-		for _, eId := range g.Edges[v] {
-			u := g.AllEdges[eId].Dst
-			if d[u] < 0 || d[u] > 1+d[v] {
-				d[u] = 1 + d[v]
-				q[qh] = u
-				qh++
-			}
-		}
-	}
-	return d
+	was := make([]bool, g.NumSites)
+	was[s] = true
+	return g.bfs(was, allRivers)
 }
 
+// Returns the distances from site s over the rivers that are either free
+// or usable by the punter, -1 for the sites that can't be reached.
+func (g *Graph) FreeDistance(s, punter int) []int {
+	was := make([]bool, g.NumSites)
+	was[s] = true
+	return g.bfs(was, punter)
+}
+
+// Returns the distances from the nearest of the sites in was.
 func (g *Graph) MSSP(was []bool) []int {
+	return g.bfs(was, allRivers)
+}
+
+// Like MSSP, but only over the rivers that are either free or usable by
+// the punter.
+func (g *Graph) FreeMSSP(was []bool, punter int) []int {
+	return g.bfs(was, punter)
+}
+
+const allRivers = -1 // the punter of bfs for the distances over all rivers
+
+// Returns the distances from the nearest of the sites in was over the
+// rivers that are either free or usable by the punter, or over all rivers,
+// -1 for the sites that can't be reached.
+func (g *Graph) bfs(was []bool, punter int) []int {
 	n := len(g.Edges)
 	q := make([]int, n)
 	qh, qt := 0, 0
@@ -84,10 +142,13 @@ func (g *Graph) MSSP(was []bool) []int {
 		u := q[qh]
 		qh++
 		for _, eId := range g.Edges[u] {
-			v := g.AllEdges[eId].Dst
-			if d[v] < 0 || d[v] > 1+d[u] {
-				d[v] = 1 + d[u]
-				q[qt] = v
+			e := &g.AllEdges[eId]
+			if punter != allRivers && e.Owner >= 0 && !e.UsableBy(punter) {
+				continue
+			}
+			if d[e.Dst] < 0 {
+				d[e.Dst] = 1 + d[u]
+				q[qt] = e.Dst
 				qt++
 			}
 		}
@@ -100,7 +161,7 @@ func (g *Graph) Dfs(u, owner int, was []bool) {
 	was[u] = true
 	for _, eId := range g.Edges[u] {
 		e := &g.AllEdges[eId]
-		if e.Owner != owner {
+		if !e.UsableBy(owner) {
 			continue
 		}
 		v := e.Dst
@@ -109,3 +170,76 @@ func (g *Graph) Dfs(u, owner int, was []bool) {
 		}
 	}
 }
+
+// Computes upper bound on the score for any player, without futures.
+func (g *Graph) ScoreUpperBound() (score int64) {
+	for i := range g.Mines {
+		for v := range g.Distance[i] {
+			score += g.SiteScore(i, v)
+		}
+	}
+	return
+}
+
+func (g *Graph) FutureUpperBound() int64 {
+	var score int64
+	for i := range g.Mines {
+		for _, d := range g.Distance[i] {
+			if int64(d) > score {
+				score = int64(d)
+			}
+		}
+	}
+	return score * score * score
+}
+
+// Returns the number of edge-disjoint paths between sites s and t,
+// counting up to limit.
+func (g *Graph) DisjointPaths(s, t, limit int) (paths int) {
+	paths, _ = g.maxFlow(s, t, limit, func(*Edge) int { return 1 })
+	return
+}
+
+// Returns the flow from site s to site t, counting up to limit, when
+// every river can carry capacity(e) units each way, and the sites still
+// reachable from s in the residual network.
+func (g *Graph) maxFlow(s, t, limit int, capacity func(e *Edge) int) (flow int, side []bool) {
+	res := make([]int, len(g.AllEdges))
+	for i := range g.AllEdges {
+		res[i] = capacity(&g.AllEdges[i])
+	}
+	prev := make([]int, g.NumSites)
+	q := make([]int, g.NumSites)
+	for {
+		for i := range prev {
+			prev[i] = -1
+		}
+		prev[s] = len(g.AllEdges)
+		qh, qt := 0, 1
+		q[0] = s
+		for qh < qt && prev[t] < 0 {
+			u := q[qh]
+			qh++
+			for _, eId := range g.Edges[u] {
+				v := g.AllEdges[eId].Dst
+				if prev[v] < 0 && res[eId] > 0 {
+					prev[v] = eId
+					q[qt] = v
+					qt++
+				}
+			}
+		}
+		if prev[t] < 0 || flow == limit {
+			side = make([]bool, g.NumSites)
+			for v := range prev {
+				side[v] = prev[v] >= 0
+			}
+			return
+		}
+		for v := t; v != s; v = g.AllEdges[prev[v]].Src {
+			res[prev[v]]--
+			res[prev[v]^1]++
+		}
+		flow++
+	}
+}
@@ -17,45 +17,30 @@ func (p *Greedy0Player) MakeMove(moves []Move) Move {
 	return p.MakeClaimMove(u, v)
 }
 
+// Claims the river next to the networks of the mines with the best gain
+// of the score, or a river within them if no river gains anything.
 func FindEdgeGreedy0(p *Greedy0Player) (int, int, bool) {
-	reachable := make([]bool, p.NumSites)
-
-	for _, v := range p.Mines {
-		if !reachable[v] {
-			dfsGreedy0(p, v, reachable)
-		}
+	fromMine := make([]bool, p.NumSites) // by the component
+	for _, m := range p.Mines {
+		fromMine[p.scorer.Component(m)] = true
 	}
 
-	bestU, bestV, bestScore := -1, -1, 0
+	bestU, bestV, bestScore := -1, -1, int64(0)
 	for _, e := range p.AllEdges {
 		if e.Owner >= 0 {
 			continue
 		}
-		if !reachable[e.Src] && !reachable[e.Dst] {
+		if !fromMine[p.scorer.Component(e.Src)] && !fromMine[p.scorer.Component(e.Dst)] {
 			continue
 		}
-		if reachable[e.Src] && reachable[e.Dst] {
+		if p.scorer.Connected(e.Src, e.Dst) {
 			if bestU < 0 {
 				bestU, bestV = e.Src, e.Dst
 			}
 			continue
 		}
 
-		cur := 0
-
-		upd := func(v int) {
-			if !reachable[v] {
-				for i := range p.Mines {
-					d := p.Distance[i][v]
-					cur += d * d
-				}
-			}
-		}
-
-		upd(e.Src)
-		upd(e.Dst)
-
-		if bestScore < cur {
+		if cur := p.scorer.ClaimGain(e.Src, e.Dst); bestScore < cur {
 			bestScore = cur
 			bestU, bestV = e.Src, e.Dst
 		}
@@ -68,17 +53,4 @@ func FindEdgeGreedy0(p *Greedy0Player) (int, int, bool) {
 	return 0, 0, false
 }
 
-func dfsGreedy0(p *Greedy0Player, v int, was []bool) {
-	was[v] = true
-	for _, eId := range p.Edges[v] {
-		if p.AllEdges[eId].Owner != p.Punter {
-			continue
-		}
-		u := p.AllEdges[eId].Dst
-		if !was[u] {
-			dfsGreedy0(p, u, was)
-		}
-	}
-}
-
 func (p *Greedy0Player) Name() string { return "greedy0" }
@@ -0,0 +1,154 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const humanListLimit = 20 // how many sites and rivers to show
+
+// HumanPlayer shows the board in the terminal and reads the moves from
+// the keyboard.
+type HumanPlayer struct {
+	BaselinePlayer
+	SiteIds
+
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p *HumanPlayer) Name() string { return "human" }
+
+func (p *HumanPlayer) MakeMove(moves []Move) Move {
+	p.PrepareForMove(moves)
+	if p.in == nil {
+		p.in = bufio.NewReader(os.Stdin)
+	}
+	if p.out == nil {
+		p.out = os.Stdout
+	}
+
+	p.showBoard(moves)
+	for {
+		fmt.Fprint(p.out, "Your move (claim a b, pass, splurge a b c..., help): ")
+		line, err := p.in.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(p.out, "\nNo more input, passing.")
+			return p.MakePassMove()
+		}
+
+		if f := strings.Fields(line); len(f) > 0 && (f[0] == "help" || f[0] == "?") {
+			p.showHelp()
+			continue
+		}
+		m, err := p.ParseTextMove(line, p.site)
+		if err != nil {
+			fmt.Fprintln(p.out, "Bad move:", err)
+			continue
+		}
+		return p.makeTextMove(m)
+	}
+}
+
+func (p *HumanPlayer) showHelp() {
+	fmt.Fprintln(p.out, "  claim a b        claim the river between sites a and b, or just: a b")
+	fmt.Fprintln(p.out, "  pass             pass the move")
+	fmt.Fprintln(p.out, "  splurge a b c    claim the path of rivers a-b-c, costs a pass per extra river")
+	fmt.Fprintln(p.out, "  option a b       buy an option on the river between sites a and b")
+	fmt.Fprintln(p.out, "  help             show this help")
+}
+
+func (p *HumanPlayer) formatSites(sites []int) string {
+	ids := make([]int, len(sites))
+	for i, v := range sites {
+		ids[i] = p.siteId(v)
+	}
+	sort.Ints(ids)
+	if len(ids) > humanListLimit {
+		return fmt.Sprintf("%v and %v more", ids[:humanListLimit], len(ids)-humanListLimit)
+	}
+	return fmt.Sprint(ids)
+}
+
+func (p *HumanPlayer) showBoard(moves []Move) {
+	fmt.Fprintln(p.out)
+	for _, m := range moves {
+		if m.Punter == p.Punter {
+			continue
+		}
+		switch m.Type {
+		case Claim, Option:
+			fmt.Fprintf(p.out, "Punter %v: %v (%v, %v)\n", m.Punter, m.Type, p.siteId(m.Source), p.siteId(m.Target))
+		case Splurge:
+			route := make([]int, len(m.Route))
+			for i, v := range m.Route {
+				route[i] = p.siteId(v)
+			}
+			fmt.Fprintf(p.out, "Punter %v: Splurge %v\n", m.Punter, route)
+		default:
+			fmt.Fprintf(p.out, "Punter %v: %v\n", m.Punter, m.Type)
+		}
+	}
+
+	scores := make([]string, len(p.scores))
+	for i, s := range p.scores {
+		scores[i] = strconv.FormatInt(s, 10)
+		if i == p.Punter {
+			scores[i] = "[" + scores[i] + "]"
+		}
+	}
+	fmt.Fprintln(p.out, "Scores:", strings.Join(scores, " "))
+	if len(p.Futures) > 0 {
+		for _, f := range p.Futures {
+			fmt.Fprintf(p.out, "Future %v -> %v: %v\n", p.siteId(f.Src), p.siteId(f.Dst), p.scorer.FutureScore(f))
+		}
+	}
+	if p.Settings.SplurgesMode {
+		fmt.Fprintln(p.out, "Passes:", p.Passes)
+	}
+
+	for i, m := range p.Mines {
+		var sites []int
+		for v, r := range p.reachableFromMine[i] {
+			if r {
+				sites = append(sites, v)
+			}
+		}
+		fmt.Fprintf(p.out, "Mine %v: %v\n", p.siteId(m), p.formatSites(sites))
+	}
+
+	type river struct {
+		u, v int
+		gain int64
+	}
+	var rivers []river
+	for _, e := range p.AllEdges {
+		if e.Owner >= 0 || e.Src > e.Dst {
+			continue
+		}
+		near := false
+		for i := range p.Mines {
+			if p.reachableFromMine[i][e.Src] || p.reachableFromMine[i][e.Dst] {
+				near = true
+			}
+		}
+		if near {
+			rivers = append(rivers, river{u: e.Src, v: e.Dst, gain: p.scorer.ClaimGain(e.Src, e.Dst)})
+		}
+	}
+	sort.SliceStable(rivers, func(a, b int) bool { return rivers[a].gain > rivers[b].gain })
+
+	fmt.Fprintf(p.out, "Free rivers next to you (%v):\n", len(rivers))
+	for i, r := range rivers {
+		if i == humanListLimit {
+			fmt.Fprintf(p.out, "  and %v more\n", len(rivers)-humanListLimit)
+			break
+		}
+		fmt.Fprintf(p.out, "  %v %v  +%v\n", p.siteId(r.u), p.siteId(r.v), r.gain)
+	}
+}
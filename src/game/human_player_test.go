@@ -0,0 +1,52 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestHumanPlayer(t *testing.T) {
+	var p HumanPlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{SplurgesMode: true})
+	p.SetSiteIds([]int{10, 11, 12, 13, 14, 15, 16, 17})
+
+	var out bytes.Buffer
+	p.out = &out
+	p.in = bufio.NewReader(strings.NewReader("help\n" +
+		"claim 10 12\n" + // no such river
+		"claim 3 4\n" + // no such sites
+		"splurge 10 11 12\n" + // no passes
+		"10 11\n" +
+		"pass\n" +
+		"splurge 11 12 13\n"))
+
+	if m := p.MakeMove(nil); m.Type != Claim || m.Source != 0 || m.Target != 1 {
+		t.Errorf("first move: got %v, want claim of (0, 1)", m)
+	}
+	for _, s := range []string{"claim a b", "no free river between 10 and 12", "no site 3", "2 rivers need 1 passes, you have 0"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output doesn't contain %q:\n%v", s, out.String())
+		}
+	}
+
+	out.Reset()
+	if m := p.MakeMove([]Move{MakeClaimMove(0, 0, 1), MakeClaimMove(1, 4, 5)}); m.Type != Pass {
+		t.Errorf("second move: got %v, want pass", m)
+	}
+	for _, s := range []string{"Punter 1: Claim (14, 15)", "Scores: [1] 1", "Mine 10: [10 11]", "11 12  +4"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output doesn't contain %q:\n%v", s, out.String())
+		}
+	}
+
+	m := p.MakeMove([]Move{MakePassMove(0), MakeClaimMove(1, 5, 6)})
+	if m.Type != Splurge || len(m.Route) != 3 || m.Route[2] != 3 {
+		t.Errorf("third move: got %v, want splurge of [1 2 3]", m)
+	}
+
+	if m := p.MakeMove(nil); m.Type != Pass {
+		t.Errorf("move without input: got %v, want pass", m)
+	}
+}
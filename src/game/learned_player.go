@@ -0,0 +1,123 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const LearnedPrefix = "learned:"
+
+// LinearModel predicts the final score margin of the punter from the
+// Features of its claim.
+type LinearModel struct {
+	Features []string  `json:"features"` // FeatureNames when the model was trained
+	Weights  []float64 `json:"weights"`
+	Bias     float64   `json:"bias"`
+}
+
+// The default weights make the greedy choice of FindEdge without the
+// defence of futures. The models the learn command fitted to the games of
+// the bots on the sample maps didn't beat them yet.
+var (
+	defaultWeights = []float64{1, 0, 0, 0, 1}
+	defaultBias    = 0.0
+)
+
+// Returns a copy of the default model, so that decoding a state into it
+// doesn't change the defaults.
+func DefaultModel() LinearModel {
+	return LinearModel{
+		Features: append([]string(nil), FeatureNames...),
+		Weights:  append([]float64(nil), defaultWeights...),
+		Bias:     defaultBias,
+	}
+}
+
+func (m *LinearModel) Predict(x []float64) float64 {
+	y := m.Bias
+	for i, w := range m.Weights {
+		y += w * x[i]
+	}
+	return y
+}
+
+// Returns an error if the model was trained on other features.
+func (m *LinearModel) Validate() error {
+	if len(m.Weights) != len(FeatureNames) || len(m.Features) != len(FeatureNames) {
+		return fmt.Errorf("the model has %v weights of %v features, want %v", len(m.Weights), len(m.Features), len(FeatureNames))
+	}
+	for i, name := range m.Features {
+		if name != FeatureNames[i] {
+			return fmt.Errorf("feature %v of the model is %v, want %v", i, name, FeatureNames[i])
+		}
+	}
+	return nil
+}
+
+func LoadLinearModel(path string) (m LinearModel, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	if err = json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("can't parse %v: %v", path, err)
+	}
+	if err = m.Validate(); err != nil {
+		return m, fmt.Errorf("%v: %v", path, err)
+	}
+	return m, nil
+}
+
+func (m *LinearModel) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// LearnedPlayer claims the river next to its network with the best
+// final margin the linear model predicts.
+type LearnedPlayer struct {
+	BaselinePlayer
+	Model LinearModel `json:"model"`
+}
+
+// Makes the player with the default model, or with the model from the
+// file given after @, e.g. learned:@weights.json.
+func MakeLearnedPlayer(spec string) (*LearnedPlayer, error) {
+	p := &LearnedPlayer{Model: DefaultModel()}
+	if spec == "" {
+		return p, nil
+	}
+	if !strings.HasPrefix(spec, "@") {
+		return nil, fmt.Errorf("bad learned bot %q, want learned:@weights.json", LearnedPrefix+spec)
+	}
+	m, err := LoadLinearModel(spec[1:])
+	if err != nil {
+		return nil, err
+	}
+	p.Model = m
+	return p, nil
+}
+
+func (p *LearnedPlayer) Name() string { return "learned" }
+
+func (p *LearnedPlayer) MakeMove(moves []Move) Move {
+	p.PrepareForMove(moves)
+
+	f := MakeFeatures(&p.BaselinePlayer)
+	var best *Edge
+	var bestY float64
+	for _, e := range p.FreeRiversNearby() {
+		if y := p.Model.Predict(f.Of(e)); best == nil || y > bestY {
+			best, bestY = e, y
+		}
+	}
+	if best == nil {
+		return p.MakePassMove()
+	}
+	return p.MakeClaimMove(best.Src, best.Dst)
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFeatures(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	p.PrepareForMove([]Move{
+		{Type: Claim, Punter: 0, Source: 0, Target: 1},
+		{Type: Claim, Punter: 1, Source: 2, Target: 3},
+	})
+
+	// (1, 2) is the only river next to the network: the best gain, no
+	// freedom left at site 2, one river from mine 0, next to punter 1.
+	f := MakeFeatures(&p)
+	if got, want := f.Of(&p.AllEdges[2]), []float64{1, 0, 0.5, 1, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("features of (1, 2): got %v, want %v", got, want)
+	}
+	// (4, 5) starts at mine 4 and has (5, 6) next to it.
+	if got, want := f.Of(&p.AllEdges[6]), []float64{0.25, 1, 1, 0, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("features of (4, 5): got %v, want %v", got, want)
+	}
+}
+
+func TestLinearModel(t *testing.T) {
+	m := DefaultModel()
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	m.Weights = []float64{1, 2, 3, 4, 5}
+	m.Bias = 0.5
+	if y := m.Predict([]float64{1, 0, 1, 0, 1}); y != 9.5 {
+		t.Errorf("prediction: got %v, want 9.5", y)
+	}
+
+	dir, err := ioutil.TempDir("", "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "weights.json")
+	if err := m.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	p, err := MakePlayerWithParams(LearnedPrefix+"@"+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.(*LearnedPlayer).Model; !reflect.DeepEqual(got, m) {
+		t.Errorf("loaded %+v, want %+v", got, m)
+	}
+	if DefaultModel().Weights[1] == 2 {
+		t.Error("the default model has changed")
+	}
+
+	m.Features = []string{"gain", "freedom"}
+	m.Save(path)
+	if _, err := LoadLinearModel(path); err == nil {
+		t.Error("no error for a model of other features")
+	}
+	if _, err := MakePlayerWithParams(LearnedPrefix+"weights.json", nil); err == nil {
+		t.Error("no error without @")
+	}
+}
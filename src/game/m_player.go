@@ -2,6 +2,24 @@ package game
 
 type MPlayer struct {
 	BaselinePlayer
+	FutureMaxDist int     `json:"futureMaxDist"` // the farthest target of a future, 0 for no limit
+	FutureBudget  float64 `json:"futureBudget"`  // share of the rivers to spend on futures
+}
+
+func (p *MPlayer) Params() []Param {
+	return []Param{
+		{Name: "futureMaxDist", Default: 0, Doc: "the farthest target of a future, 0 for no limit", Min: 0, Max: 50, Integer: true},
+		{Name: "futureBudget", Default: futureBudgetShare, Doc: "share of the rivers to spend on futures", Min: 0, Max: 1},
+	}
+}
+
+func (p *MPlayer) SetParam(name string, value float64) {
+	switch name {
+	case "futureMaxDist":
+		p.FutureMaxDist = int(value)
+	case "futureBudget":
+		p.FutureBudget = value
+	}
 }
 
 func (p *MPlayer) Setup(punter, punters int, m Map, s Settings) {
@@ -14,27 +32,14 @@ func (p *MPlayer) SetupFutures() {
 	if !p.Settings.FuturesMode {
 		return
 	}
-	maxDist := p.NumSites / 5
-	if maxDist > 15 {
-		maxDist = 15
+	var allowed func(i, v int) bool
+	if p.FutureMaxDist > 0 {
+		allowed = func(i, v int) bool { return p.Distance[i][v] <= p.FutureMaxDist }
 	}
-	for i, m := range p.Mines {
-		best, bestDist := -1, -1
-		for j := 0; j < p.NumSites; j++ {
-			if j == m {
-				continue
-			}
-			d := p.Distance[i][j]
-			//			if d > maxDist {
-			//				continue
-			//			}
-			if best < 0 || bestDist < d {
-				best, bestDist = j, d
-			}
-		}
-		if best >= 0 {
-			p.Futures = append(p.Futures, Future{Src: m, Dst: best})
-		}
+	planner := MakeFuturePlanner(&p.Graph, p.Punters)
+	planner.BudgetShare = p.FutureBudget
+	for _, fe := range planner.ChooseFutures(allowed) {
+		p.Futures = append(p.Futures, fe.Future)
 	}
 }
 
@@ -0,0 +1,207 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// The prefix of the meta bot with the selection table from a file,
+	// e.g. "meta:@table.json".
+	MetaPrefix = "meta:"
+
+	metaFallback = "random1" // the bot for an empty table
+)
+
+// GameFeatures describes a game at setup.
+type GameFeatures struct {
+	Sites    int  `json:"sites"`
+	Rivers   int  `json:"rivers"`
+	Mines    int  `json:"mines"`
+	Punters  int  `json:"punters"`
+	Futures  bool `json:"futures,omitempty"`
+	Splurges bool `json:"splurges,omitempty"`
+	Options  bool `json:"options,omitempty"`
+}
+
+func MakeGameFeatures(punters int, m Map, s Settings) GameFeatures {
+	return GameFeatures{
+		Sites:    len(m.Sites),
+		Rivers:   len(m.Rivers),
+		Mines:    len(m.Mines),
+		Punters:  punters,
+		Futures:  s.FuturesMode,
+		Splurges: s.SplurgesMode,
+		Options:  s.OptionsMode,
+	}
+}
+
+// The games are compared by the logarithms of the size, the number of
+// mines and punters, by the density, and mostly by the extensions: twice
+// as many rivers count as much as one more river per site, another
+// extension as 256 times as many rivers.
+func (f *GameFeatures) vector() []float64 {
+	flag := func(b bool) float64 {
+		if b {
+			return 8
+		}
+		return 0
+	}
+	return []float64{
+		math.Log2(float64(f.Rivers) + 1),
+		float64(f.Rivers) / math.Max(float64(f.Sites), 1),
+		math.Log2(float64(f.Mines) + 1),
+		math.Log2(math.Max(float64(f.Punters), 1)),
+		flag(f.Futures),
+		flag(f.Splurges),
+		flag(f.Options),
+	}
+}
+
+func (f *GameFeatures) distance(g *GameFeatures) (d float64) {
+	a, b := f.vector(), g.vector()
+	for i := range a {
+		d += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return
+}
+
+// Selection is the best bot in a kind of game, as found in the playground.
+type Selection struct {
+	GameFeatures
+	Bot    string             `json:"bot"`
+	Params map[string]float64 `json:"params,omitempty"`
+	Margin float64            `json:"margin"` // the mean score margin over the best of the others, as a share of the upper bound
+	Games  int                `json:"games"`
+}
+
+// SelectionTable chooses the bot of the most similar game.
+type SelectionTable []Selection
+
+func (t SelectionTable) Choose(f *GameFeatures) (bot string, params map[string]float64) {
+	bot, bestD := metaFallback, math.Inf(1)
+	for i := range t {
+		if d := t[i].distance(f); d < bestD {
+			bot, params, bestD = t[i].Bot, t[i].Params, d
+		}
+	}
+	return
+}
+
+// Returns an error if a bot of the table can't be made with its parameters,
+// or is a meta bot itself.
+func (t SelectionTable) Validate() error {
+	for _, s := range t {
+		if s.Bot == "meta" || strings.HasPrefix(s.Bot, MetaPrefix) {
+			return fmt.Errorf("the meta bot can't choose %v", s.Bot)
+		}
+		if _, err := MakePlayerWithParams(s.Bot, s.Params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func LoadSelectionTable(path string) (t SelectionTable, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("can't parse %v: %v", path, err)
+	}
+	if err = t.Validate(); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return t, nil
+}
+
+// MetaPlayer chooses the bot from the features of the game at setup and
+// lets it play. The choice is kept in the state, so that the same bot
+// makes all moves in the offline mode.
+type MetaPlayer struct {
+	Features GameFeatures       `json:"features"`
+	Choice   string             `json:"choice"`
+	Params   map[string]float64 `json:"params,omitempty"` // of the chosen bot
+	Bot      Player             `json:"bot"`
+	table    SelectionTable
+}
+
+// Makes the player with the default table, or with the table from the
+// file given after @, e.g. meta:@table.json.
+func MakeMetaPlayer(spec string) (*MetaPlayer, error) {
+	p := &MetaPlayer{table: defaultSelection}
+	if spec == "" {
+		return p, nil
+	}
+	if !strings.HasPrefix(spec, "@") {
+		return nil, fmt.Errorf("bad meta bot %q, want meta:@table.json", MetaPrefix+spec)
+	}
+	t, err := LoadSelectionTable(spec[1:])
+	if err != nil {
+		return nil, err
+	}
+	p.table = t
+	return p, nil
+}
+
+func (p *MetaPlayer) Setup(punter, punters int, m Map, s Settings) {
+	p.Features = MakeGameFeatures(punters, m, s)
+	p.Choice, p.Params = p.table.Choose(&p.Features)
+	bot, err := MakePlayerWithParams(p.Choice, p.Params)
+	if err != nil {
+		panic(err.Error()) // the table was validated
+	}
+	p.Bot = bot
+	p.Bot.Setup(punter, punters, m, s)
+}
+
+func (p *MetaPlayer) MakeMove(moves []Move) Move { return p.Bot.MakeMove(moves) }
+
+func (p *MetaPlayer) Name() string { return "meta" }
+
+func (p *MetaPlayer) GetPunter() int { return p.Bot.GetPunter() }
+
+func (p *MetaPlayer) GetFutures() []Future { return p.Bot.GetFutures() }
+
+func (p *MetaPlayer) SetFutures(futures []Future) {
+	if fa, ok := p.Bot.(FuturesAware); ok {
+		fa.SetFutures(futures)
+	}
+}
+
+func (p *MetaPlayer) SetTimeBudget(budget time.Duration) {
+	if t, ok := p.Bot.(TimeLimited); ok {
+		t.SetTimeBudget(budget)
+	}
+}
+
+// The chosen bot is downgraded, or plays on by itself.
+func (p *MetaPlayer) Downgrade() Player {
+	if d, ok := p.Bot.(Downgradable); ok {
+		return d.Downgrade()
+	}
+	return p.Bot
+}
+
+// Makes the chosen bot before its state is decoded.
+func (p *MetaPlayer) UnmarshalJSON(data []byte) error {
+	var header struct {
+		Choice string             `json:"choice"`
+		Params map[string]float64 `json:"params"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	bot, err := MakePlayerWithParams(header.Choice, header.Params)
+	if err != nil {
+		return err
+	}
+	p.Bot = bot
+	type plain MetaPlayer
+	return json.Unmarshal(data, (*plain)(p))
+}
@@ -0,0 +1,46 @@
+package game
+
+// The default selection table, printed by the metatable command with --go
+// from the games of the bots on the sample maps, see the README.
+var defaultSelection = SelectionTable{
+	{GameFeatures: GameFeatures{Sites: 8, Rivers: 12, Mines: 2, Punters: 2}, Bot: "voronoi", Margin: 0.4375, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 8, Rivers: 12, Mines: 2, Punters: 2, Futures: true}, Bot: "steiner", Margin: 0.3594, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 8, Rivers: 12, Mines: 2, Punters: 4}, Bot: "steiner", Margin: 0.0781, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 8, Rivers: 12, Mines: 2, Punters: 4, Futures: true}, Bot: "steiner", Margin: 0.1016, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 38, Rivers: 60, Mines: 4, Punters: 2}, Bot: "steiner", Margin: 0.1242, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 38, Rivers: 60, Mines: 4, Punters: 2, Futures: true}, Bot: "steiner", Margin: 0.2528, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 38, Rivers: 60, Mines: 4, Punters: 4}, Bot: "steiner", Margin: 0.0952, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 38, Rivers: 60, Mines: 4, Punters: 4, Futures: true}, Bot: "random1", Margin: -0.0085, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 27, Rivers: 65, Mines: 4, Punters: 2}, Bot: "random1", Margin: 0.1180, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 27, Rivers: 65, Mines: 4, Punters: 2, Futures: true}, Bot: "m", Margin: 0.1362, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 27, Rivers: 65, Mines: 4, Punters: 4}, Bot: "steiner", Margin: 0.0672, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 27, Rivers: 65, Mines: 4, Punters: 4, Futures: true}, Bot: "m", Margin: 0.0494, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 42, Rivers: 81, Mines: 3, Punters: 2}, Bot: "random2", Margin: 0.4272, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 42, Rivers: 81, Mines: 3, Punters: 2, Futures: true}, Bot: "m", Margin: 0.2992, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 42, Rivers: 81, Mines: 3, Punters: 4}, Bot: "random1", Margin: 0.0571, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 42, Rivers: 81, Mines: 3, Punters: 4, Futures: true}, Bot: "random1", Margin: 0.0111, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 86, Rivers: 123, Mines: 4, Punters: 2}, Bot: "voronoi", Margin: 0.2763, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 86, Rivers: 123, Mines: 4, Punters: 2, Futures: true}, Bot: "voronoi", Margin: 0.1788, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 86, Rivers: 123, Mines: 4, Punters: 4}, Bot: "voronoi", Margin: 0.0085, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 86, Rivers: 123, Mines: 4, Punters: 4, Futures: true}, Bot: "voronoi", Margin: 0.0092, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 97, Rivers: 187, Mines: 4, Punters: 2}, Bot: "steiner", Margin: 0.1736, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 97, Rivers: 187, Mines: 4, Punters: 2, Futures: true}, Bot: "steiner", Margin: 0.2858, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 97, Rivers: 187, Mines: 4, Punters: 4}, Bot: "random2", Margin: 0.0035, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 97, Rivers: 187, Mines: 4, Punters: 4, Futures: true}, Bot: "m", Margin: -0.0119, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 301, Rivers: 386, Mines: 5, Punters: 2}, Bot: "random1", Margin: 0.1769, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 301, Rivers: 386, Mines: 5, Punters: 2, Futures: true}, Bot: "steiner", Margin: 0.1577, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 301, Rivers: 386, Mines: 5, Punters: 4}, Bot: "random1", Margin: 0.0150, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 301, Rivers: 386, Mines: 5, Punters: 4, Futures: true}, Bot: "steiner", Margin: -0.0015, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 488, Rivers: 945, Mines: 8, Punters: 2}, Bot: "random2", Margin: 0.2943, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 488, Rivers: 945, Mines: 8, Punters: 2, Futures: true}, Bot: "m", Margin: 0.3703, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 488, Rivers: 945, Mines: 8, Punters: 4}, Bot: "random1", Margin: 0.0563, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 488, Rivers: 945, Mines: 8, Punters: 4, Futures: true}, Bot: "m", Margin: 0.1089, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 961, Rivers: 1751, Mines: 32, Punters: 2}, Bot: "random1", Margin: 0.1713, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 961, Rivers: 1751, Mines: 32, Punters: 2, Futures: true}, Bot: "steiner", Margin: 0.1242, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 961, Rivers: 1751, Mines: 32, Punters: 4}, Bot: "steiner", Margin: 0.1241, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 961, Rivers: 1751, Mines: 32, Punters: 4, Futures: true}, Bot: "steiner", Margin: 0.1364, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 1560, Rivers: 2197, Mines: 12, Punters: 2}, Bot: "random2", Margin: 0.3997, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 1560, Rivers: 2197, Mines: 12, Punters: 2, Futures: true}, Bot: "random2", Margin: 0.1435, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 1560, Rivers: 2197, Mines: 12, Punters: 4}, Bot: "random2", Margin: 0.0313, Games: 4},
+	{GameFeatures: GameFeatures{Sites: 1560, Rivers: 2197, Mines: 12, Punters: 4, Futures: true}, Bot: "random2", Margin: 0.0511, Games: 4},
+}
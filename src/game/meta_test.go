@@ -0,0 +1,84 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSelectionTable(t *testing.T) {
+	table := SelectionTable{
+		{GameFeatures: GameFeatures{Sites: 30, Rivers: 60, Mines: 3, Punters: 2}, Bot: "random1", Params: map[string]float64{"depth": 6}},
+		{GameFeatures: GameFeatures{Sites: 30, Rivers: 60, Mines: 3, Punters: 2, Futures: true}, Bot: "m"},
+		{GameFeatures: GameFeatures{Sites: 2000, Rivers: 3000, Mines: 12, Punters: 4}, Bot: "voronoi"},
+	}
+	tests := []struct {
+		f    GameFeatures
+		want string
+	}{
+		{GameFeatures{Sites: 8, Rivers: 12, Mines: 2, Punters: 2}, "random1"},
+		{GameFeatures{Sites: 8, Rivers: 12, Mines: 2, Punters: 2, Futures: true}, "m"},
+		{GameFeatures{Sites: 1000, Rivers: 2000, Mines: 8, Punters: 8}, "voronoi"},
+		{GameFeatures{Sites: 1000, Rivers: 2000, Mines: 8, Punters: 8, Futures: true}, "m"},
+	}
+	for _, test := range tests {
+		if got, _ := table.Choose(&test.f); got != test.want {
+			t.Errorf("%+v: got %v, want %v", test.f, got, test.want)
+		}
+	}
+	if _, params := table.Choose(&tests[0].f); params["depth"] != 6 {
+		t.Errorf("params of random1: got %v, want depth 6", params)
+	}
+	if got, _ := (SelectionTable{}).Choose(&tests[0].f); got != metaFallback {
+		t.Errorf("empty table: got %v, want %v", got, metaFallback)
+	}
+
+	if err := defaultSelection.Validate(); err != nil {
+		t.Error(err)
+	}
+	if err := table.Validate(); err != nil {
+		t.Error(err)
+	}
+	for _, bad := range []Selection{
+		{Bot: "nobody"},
+		{Bot: "random1", Params: map[string]float64{"nonsense": 1}},
+		{Bot: "meta"},
+	} {
+		if err := (SelectionTable{bad}).Validate(); err == nil {
+			t.Errorf("no error for %v %v", bad.Bot, bad.Params)
+		}
+	}
+}
+
+func TestMetaPlayerState(t *testing.T) {
+	p := MakePlayer("meta").(*MetaPlayer)
+	p.table = SelectionTable{{
+		GameFeatures: GameFeatures{Sites: 8, Rivers: 6, Mines: 4, Punters: 2},
+		Bot:          "m",
+		Params:       map[string]float64{"futureMaxDist": 2},
+	}}
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	if p.Choice != "m" || p.Features.Rivers != 6 {
+		t.Fatalf("choice %v for %+v, want m", p.Choice, p.Features)
+	}
+
+	// The restored player plays on with the same bot, whatever the table.
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := MakePlayer("meta").(*MetaPlayer)
+	if err := json.Unmarshal(data, q); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := q.Bot.(*MPlayer); !ok || q.Choice != "m" || !reflect.DeepEqual(q.Features, p.Features) {
+		t.Fatalf("restored %v (%T) for %+v", q.Choice, q.Bot, q.Features)
+	}
+	if m := q.Bot.(*MPlayer); m.FutureMaxDist != 2 || q.Params["futureMaxDist"] != 2 {
+		t.Errorf("restored futureMaxDist %v, params %v, want 2", m.FutureMaxDist, q.Params)
+	}
+	moves := []Move{MakePassMove(0), MakePassMove(1)}
+	if a, b := p.MakeMove(moves), q.MakeMove(moves); !reflect.DeepEqual(a, b) {
+		t.Errorf("moves differ: %v and %v", a, b)
+	}
+}
@@ -0,0 +1,119 @@
+package game
+
+import "sort"
+
+// Vulnerability is a pair of sites the punter wants connected, a future
+// or two mines, that the others can cut apart by claiming a few free
+// rivers.
+type Vulnerability struct {
+	Src    int   // the mine
+	Dst    int   // the target of the future or the other mine
+	Future bool  // true for a future, false for a link between mines
+	Value  int64 // what the punter loses if the sites are cut apart
+	Cut    int   // free rivers the others need to claim
+	Rivers []int // edges of a minimum cut, going from the side of Src
+}
+
+// Returns the minimum number of free rivers the others need to claim to
+// cut site t off from site s for the punter, counting up to limit, and
+// the edges of such a cut if it is smaller than limit. The rivers usable
+// by the punter can't be cut.
+func (g *Graph) MinCut(s, t, punter, limit int) (cut int, rivers []int) {
+	cut, side := g.maxFlow(s, t, limit, func(e *Edge) int {
+		switch {
+		case e.UsableBy(punter):
+			return limit
+		case e.Owner < 0:
+			return 1
+		}
+		return 0
+	})
+	if cut == limit {
+		return
+	}
+	for i := range g.AllEdges {
+		e := &g.AllEdges[i]
+		if e.Owner < 0 && side[e.Src] && !side[e.Dst] {
+			rivers = append(rivers, i)
+		}
+	}
+	return
+}
+
+func (p *BaselinePlayer) vulnerability(src, dst int, future bool, value int64, limit int) (Vulnerability, bool) {
+	if value <= 0 || p.scorer.Connected(src, dst) {
+		return Vulnerability{}, false
+	}
+	cut, rivers := p.MinCut(src, dst, p.Punter, limit)
+	if cut == 0 || cut == limit {
+		return Vulnerability{}, false
+	}
+	return Vulnerability{Src: src, Dst: dst, Future: future, Value: value, Cut: cut, Rivers: rivers}, true
+}
+
+func sortVulnerabilities(vs []Vulnerability) {
+	sort.SliceStable(vs, func(a, b int) bool {
+		if vs[a].Cut != vs[b].Cut {
+			return vs[a].Cut < vs[b].Cut
+		}
+		return vs[a].Value > vs[b].Value
+	})
+}
+
+// Returns the unfinished futures the others can cut off with fewer than
+// limit rivers, the smallest cuts first and the most valuable futures
+// first among them. Futures already lost are left out.
+func (p *BaselinePlayer) FutureVulnerabilities(limit int) (vs []Vulnerability) {
+	for _, f := range p.Futures {
+		i := p.MineIndex(f.Src)
+		if i < 0 || p.Distance[i][f.Dst] < 0 {
+			continue
+		}
+		// A cut turns the bonus into a penalty.
+		d := int64(p.Distance[i][f.Dst])
+		if v, ok := p.vulnerability(f.Src, f.Dst, true, 2*d*d*d, limit); ok {
+			vs = append(vs, v)
+		}
+	}
+	sortVulnerabilities(vs)
+	return
+}
+
+// Like FutureVulnerabilities, but also with the links from the mines the
+// punter has rivers at to the other mines.
+func (p *BaselinePlayer) Vulnerabilities(limit int) []Vulnerability {
+	vs := p.FutureVulnerabilities(limit)
+	own := make([]bool, p.NumSites)
+	for _, e := range p.AllEdges {
+		if e.UsableBy(p.Punter) {
+			own[e.Src] = true
+		}
+	}
+	for i, a := range p.Mines {
+		if !own[a] {
+			continue
+		}
+		for j, b := range p.Mines {
+			if j == i || own[b] && j < i {
+				continue
+			}
+			value := p.SiteScore(i, b) + p.SiteScore(j, a)
+			if v, ok := p.vulnerability(a, b, false, value, limit); ok {
+				vs = append(vs, v)
+			}
+		}
+	}
+	sortVulnerabilities(vs)
+	return vs
+}
+
+// Returns a river of the most urgent vulnerability the others can cut
+// with at most maxCut rivers.
+func (p *BaselinePlayer) DefendMove(maxCut int) (u, v int, ok bool) {
+	vs := p.Vulnerabilities(maxCut + 1)
+	if len(vs) == 0 {
+		return
+	}
+	e := &p.AllEdges[vs[0].Rivers[0]]
+	return e.Src, e.Dst, true
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMinCut(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+	g.SetEdgeOwnership(0, 1, 0)
+	g.SetEdgeOwnership(4, 5, 1)
+
+	tests := []struct {
+		s, t, punter, limit int
+		cut                 int
+		rivers              []int
+	}{
+		// The own river can't be cut, (1, 2) is the first free one.
+		{0, 3, 0, 3, 1, []int{2}},
+		{4, 6, 1, 3, 2, []int{8, 10}},
+		{4, 6, 1, 2, 2, nil},
+		// Punter 1 holds (4, 5), only (4, 6) is left for punter 0.
+		{6, 4, 0, 3, 1, []int{11}},
+		{0, 4, 0, 3, 0, nil},
+	}
+	for _, test := range tests {
+		cut, rivers := g.MinCut(test.s, test.t, test.punter, test.limit)
+		if cut != test.cut || !reflect.DeepEqual(rivers, test.rivers) {
+			t.Errorf("cut from %v to %v for %v up to %v: got %v %v, want %v %v",
+				test.s, test.t, test.punter, test.limit, cut, rivers, test.cut, test.rivers)
+		}
+	}
+}
+
+func TestVulnerabilities(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{FuturesMode: true})
+	p.SetFutures([]Future{{Src: 0, Dst: 3}})
+	p.PrepareForMove([]Move{
+		{Type: Claim, Punter: 0, Source: 4, Target: 5},
+		{Type: Claim, Punter: 1, Source: 2, Target: 3},
+	})
+
+	// The future is lost already, the link from mine 4 to mine 6 is not.
+	vs := p.Vulnerabilities(3)
+	want := []Vulnerability{{Src: 4, Dst: 6, Value: 2, Cut: 2, Rivers: []int{8, 10}}}
+	if !reflect.DeepEqual(vs, want) {
+		t.Errorf("vulnerabilities: got %+v, want %+v", vs, want)
+	}
+	if vs := p.FutureVulnerabilities(3); len(vs) != 0 {
+		t.Errorf("future vulnerabilities: got %+v, want none", vs)
+	}
+	if u, v, ok := p.DefendMove(2); !ok || u != 5 || v != 6 {
+		t.Errorf("defend move: got (%v, %v, %v), want (5, 6)", u, v, ok)
+	}
+	if _, _, ok := p.DefendMove(1); ok {
+		t.Error("defend move: no cut of a single river expected")
+	}
+}
+
+func TestFindEdgeDefendsFutures(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{FuturesMode: true})
+	p.SetFutures([]Future{{Src: 0, Dst: 3}})
+	p.PrepareForMove([]Move{{Type: Claim, Punter: 0, Source: 0, Target: 1}})
+
+	if u, v, ok := p.FindEdge(); !ok || u != 1 || v != 2 {
+		t.Errorf("got (%v, %v, %v), want (1, 2)", u, v, ok)
+	}
+}
@@ -8,6 +8,7 @@ const (
 	Claim = iota
 	Pass
 	Splurge
+	Option
 )
 
 type Move struct {
@@ -26,6 +27,8 @@ func (mt MoveType) String() string {
 		return "Pass"
 	case Splurge:
 		return "Splurge"
+	case Option:
+		return "Option"
 	}
 	return "Unknown move"
 }
@@ -38,6 +41,8 @@ func (m Move) String() string {
 		return fmt.Sprintf("Punter=%v, Pass", m.Punter)
 	case Splurge:
 		return fmt.Sprintf("Punter=%v, Splurge Route=%v", m.Punter, m.Route)
+	case Option:
+		return fmt.Sprintf("Punter=%v, Option River=(%v,%v)", m.Punter, m.Source, m.Target)
 	}
 	return "Bad Move"
 }
@@ -53,3 +58,7 @@ func MakePassMove(punter int) Move {
 func MakeSplurgeMove(punter int, route []int) Move {
 	return Move{Type: Splurge, Punter: punter, Route: route}
 }
+
+func MakeOptionMove(punter, source, target int) Move {
+	return Move{Type: Option, Punter: punter, Source: source, Target: target, Route: nil}
+}
@@ -0,0 +1,148 @@
+package game
+
+import "sort"
+
+// MineOutlook is how things stand at a mine in the opening.
+type MineOutlook struct {
+	Mine       int     // index of the mine in Graph.Mines
+	Own        int     // rivers at the mine owned by the punter
+	Free       int     // free rivers at the mine
+	Threatened int     // neighbours of the mine already reached by the others
+	Value      int64   // the score of all sites reachable from the mine over free and own rivers
+	Risk       float64 // estimated chance the others close the mine before the punter's next move
+}
+
+// Secured mines have a river of the punter. Lost ones have no free
+// rivers left or the others have closed everything around them.
+func (mo *MineOutlook) Secured() bool { return mo.Own > 0 }
+
+func (mo *MineOutlook) Lost() bool { return mo.Free == 0 || mo.Value == 0 }
+
+// Opening decides which mines to secure first: the valuable ones the
+// others are about to close, with a river to the least threatened
+// neighbour. Once every mine is secured or lost, it takes the other free
+// rivers at the mines, which keeps the others away from them.
+type Opening struct {
+	g       *Graph
+	punter  int
+	punters int
+}
+
+func MakeOpening(g *Graph, punter, punters int) Opening {
+	return Opening{g: g, punter: punter, punters: punters}
+}
+
+// Returns true if a river of another punter touches site v.
+func (o *Opening) reachedByOthers(v int) bool {
+	for _, eId := range o.g.Edges[v] {
+		e := &o.g.AllEdges[eId]
+		if e.Owner >= 0 && e.Owner != o.punter {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Opening) freeDegree(v int) (n int) {
+	for _, eId := range o.g.Edges[v] {
+		if o.g.AllEdges[eId].Owner < 0 {
+			n++
+		}
+	}
+	return
+}
+
+func (o *Opening) outlook(i int) (mo MineOutlook) {
+	g := o.g
+	mo.Mine = i
+	for _, eId := range g.Edges[g.Mines[i]] {
+		e := &g.AllEdges[eId]
+		switch {
+		case e.Owner < 0:
+			mo.Free++
+			if o.reachedByOthers(e.Dst) {
+				mo.Threatened++
+			}
+		case e.UsableBy(o.punter):
+			mo.Own++
+		}
+	}
+	for v, d := range g.FreeDistance(g.Mines[i], o.punter) {
+		if d >= 0 {
+			mo.Value += g.SiteScore(i, v)
+		}
+	}
+
+	// Every other punter takes one of the free rivers before our next
+	// move with the chance of 1/Free, more likely if it is already next
+	// to the mine.
+	if mo.Free > 0 {
+		others := float64(o.punters - 1)
+		pressure := others * (1 + float64(mo.Threatened)/float64(mo.Free))
+		mo.Risk = pressure / float64(mo.Free)
+		if mo.Risk > 1 {
+			mo.Risk = 1
+		}
+	}
+	return
+}
+
+// Returns the outlook of every mine, the most urgent first.
+func (o *Opening) Mines() []MineOutlook {
+	mines := make([]MineOutlook, len(o.g.Mines))
+	for i := range mines {
+		mines[i] = o.outlook(i)
+	}
+	sort.SliceStable(mines, func(a, b int) bool {
+		return float64(mines[a].Value)*mines[a].Risk > float64(mines[b].Value)*mines[b].Risk
+	})
+	return mines
+}
+
+// Returns the river at the most urgent mine that is not secured, or at
+// the most urgent secured one, false if all mines are lost.
+func (o *Opening) Move() (u, v int, ok bool) {
+	mines := o.Mines()
+	sort.SliceStable(mines, func(a, b int) bool { return !mines[a].Secured() && mines[b].Secured() })
+	for _, mo := range mines {
+		if mo.Lost() {
+			continue
+		}
+
+		g := o.g
+		m := g.Mines[mo.Mine]
+		bestScore := -1
+		for _, eId := range g.Edges[m] {
+			e := &g.AllEdges[eId]
+			if e.Owner >= 0 {
+				continue
+			}
+			score := 2 * o.freeDegree(e.Dst)
+			if !o.reachedByOthers(e.Dst) {
+				score++
+			}
+			if score > bestScore {
+				bestScore = score
+				u, v = e.Src, e.Dst
+			}
+		}
+		return u, v, true
+	}
+	return
+}
+
+// Returns the opening move while the punter has claimed fewer than k
+// rivers.
+func (p *BaselinePlayer) OpeningMove(k int) (u, v int, ok bool) {
+	claimed := 0
+	for _, e := range p.AllEdges {
+		if e.Owner == p.Punter {
+			claimed++
+		}
+	}
+	if claimed/2 >= k {
+		return
+	}
+	o := MakeOpening(&p.Graph, p.Punter, p.Punters)
+	return o.Move()
+}
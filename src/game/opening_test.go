@@ -0,0 +1,42 @@
+package game
+
+import "testing"
+
+func TestOpening(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	o := MakeOpening(&p.Graph, 0, 2)
+
+	// Mine 0 has a single river to sites worth 14, the others are worth 2
+	// with two rivers each. Mine 7 has no rivers.
+	mines := o.Mines()
+	if mines[0].Mine != 0 || mines[0].Value != 14 || mines[0].Risk != 1 {
+		t.Errorf("most urgent mine: got %+v, want mine 0", mines[0])
+	}
+	if last := mines[len(mines)-1]; last.Mine != 3 || !last.Lost() {
+		t.Errorf("least urgent mine: got %+v, want lost mine 7", last)
+	}
+	if u, v, ok := o.Move(); !ok || u != 0 || v != 1 {
+		t.Errorf("first move: got (%v, %v, %v), want (0, 1)", u, v, ok)
+	}
+
+	// Mine 0 is lost, mine 4 is next.
+	p.SetEdgeOwnership(0, 1, 1)
+	if u, v, ok := o.Move(); !ok || u != 4 || v != 5 {
+		t.Errorf("second move: got (%v, %v, %v), want (4, 5)", u, v, ok)
+	}
+
+	// Mine 4 is secured, mine 6 is not.
+	p.SetEdgeOwnership(4, 5, 0)
+	p.SetEdgeOwnership(5, 6, 1)
+	if u, v, ok := o.Move(); !ok || u != 6 || v != 4 {
+		t.Errorf("third move: got (%v, %v, %v), want (6, 4)", u, v, ok)
+	}
+	if _, _, ok := p.OpeningMove(1); ok {
+		t.Error("opening move after the opening")
+	}
+	p.SetEdgeOwnership(4, 6, 1)
+	if _, _, ok := o.Move(); ok {
+		t.Error("move with all mines lost or closed")
+	}
+}
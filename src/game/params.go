@@ -0,0 +1,110 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Param is a numeric knob of a bot. Min and Max bound its values, which
+// are also the default range of the tune command.
+type Param struct {
+	Name     string
+	Default  float64
+	Doc      string
+	Min, Max float64
+	Integer  bool
+}
+
+// The seed of the random choices of a bot.
+func seedParam(doc string) Param {
+	return Param{Name: "seed", Default: 42, Doc: doc, Min: 0, Max: math.MaxInt32, Integer: true}
+}
+
+// Returns an error if the value is out of the bounds of the parameter.
+func (p *Param) Check(value float64) error {
+	if value < p.Min || value > p.Max {
+		return fmt.Errorf("parameter %v must be in [%v, %v], got %v", p.Name, p.Min, p.Max, value)
+	}
+	if p.Integer && value != math.Trunc(value) {
+		return fmt.Errorf("parameter %v must be an integer, got %v", p.Name, value)
+	}
+	return nil
+}
+
+// Tunable players declare their parameters and accept values for them.
+// The values are json fields of the player, so they survive between
+// moves in the offline mode.
+type Tunable interface {
+	Params() []Param
+	SetParam(name string, value float64)
+}
+
+// Returns the parameters the player declares, nil if it is not tunable.
+func PlayerParams(p Player) []Param {
+	if t, ok := p.(Tunable); ok {
+		return t.Params()
+	}
+	return nil
+}
+
+// The bots configured by the rest of the name after a prefix, see
+// MakePlayerWithParams.
+func prefixedBots() map[string]func(spec string) (Player, error) {
+	return map[string]func(spec string) (Player, error){
+		ScriptedPrefix: func(spec string) (Player, error) { return MakeScriptedPlayer(spec) },
+		LearnedPrefix:  func(spec string) (Player, error) { return MakeLearnedPlayer(spec) },
+		MetaPrefix:     func(spec string) (Player, error) { return MakeMetaPlayer(spec) },
+	}
+}
+
+// Creates the player and sets its parameters. Parameters that are not
+// given get their default values; parameters the player doesn't declare
+// are an error.
+func MakePlayerWithParams(name string, params map[string]float64) (Player, error) {
+	for prefix, makeBot := range prefixedBots() {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if len(params) > 0 {
+			return nil, fmt.Errorf("%v bots have no parameters", strings.TrimSuffix(prefix, ":"))
+		}
+		p, err := makeBot(name[len(prefix):])
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+
+	p := newPlayer(name)
+	if p == nil {
+		return nil, fmt.Errorf("unknown bot: %v", name)
+	}
+
+	declared := make(map[string]Param)
+	t, _ := p.(Tunable)
+	if t != nil {
+		for _, param := range t.Params() {
+			declared[param.Name] = param
+			t.SetParam(param.Name, param.Default)
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for k := range params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		param, ok := declared[k]
+		if !ok {
+			return nil, fmt.Errorf("bot %v has no parameter %v", name, k)
+		}
+		if err := param.Check(params[k]); err != nil {
+			return nil, fmt.Errorf("bot %v: %v", name, err)
+		}
+		t.SetParam(k, params[k])
+	}
+	return p, nil
+}
@@ -0,0 +1,44 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParamBounds(t *testing.T) {
+	for _, name := range allPlayers {
+		for _, param := range PlayerParams(MakePlayer(name)) {
+			if param.Min >= param.Max {
+				t.Errorf("%v: parameter %v has no range", name, param.Name)
+			}
+			if err := param.Check(param.Default); err != nil {
+				t.Errorf("%v: default: %v", name, err)
+			}
+		}
+	}
+
+	for _, bad := range []struct {
+		bot    string
+		params map[string]float64
+	}{
+		{"lookahead", map[string]float64{"k": -1}},
+		{"random1", map[string]float64{"depth": 2.5}},
+		{"random1", map[string]float64{"discount": 2}},
+		{"endgame:random1", map[string]float64{"endgameRivers": 100}},
+	} {
+		if _, err := MakePlayerWithParams(bad.bot, bad.params); err == nil {
+			t.Errorf("%v %v: no error", bad.bot, bad.params)
+		}
+	}
+}
+
+func TestTopKWithoutK(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	p.PrepareForMove(nil)
+	ev := new(gainEvaluator)
+	ev.Prepare(&p)
+	if e := (topKSelector{K: 0}).Select(&p, ev, p.FreeRiversNearby(), rand.New(rand.NewSource(1))); e == nil {
+		t.Error("no river selected")
+	}
+}
@@ -8,7 +8,22 @@ type Player interface {
 	GetFutures() []Future
 }
 
+// FuturesAware players accept futures chosen for them, e.g. to test
+// them in a given position.
+type FuturesAware interface {
+	SetFutures(futures []Future)
+}
+
 func MakePlayer(name string) Player {
+	p, err := MakePlayerWithParams(name, nil)
+	if err != nil {
+		panic(err.Error())
+	}
+	return p
+}
+
+// Returns a player with zero parameters, nil if the name is unknown.
+func newPlayer(name string) Player {
 	switch name {
 	case "zombie":
 		return new(ZombiePlayer)
@@ -24,6 +39,19 @@ func MakePlayer(name string) Player {
 		return new(Random2Player)
 	case "m":
 		return new(MPlayer)
+	case "steiner":
+		return new(SteinerPlayer)
+	case "voronoi":
+		return new(VoronoiPlayer)
+	case "learned":
+		return &LearnedPlayer{Model: DefaultModel()}
+	case "meta":
+		return &MetaPlayer{table: defaultSelection}
+	case "human":
+		return new(HumanPlayer)
+	}
+	if _, ok := compositions[name]; ok {
+		return &ComposedPlayer{Bot: name}
 	}
-	panic("Unknown name: " + name)
+	return newEndgamePlayer(name)
 }
@@ -6,6 +6,9 @@ import (
 
 type Random0Player struct {
 	BaselinePlayer
+	Depth             int   `json:"depth"`   // how far to look from the new site
+	Opening           int   `json:"opening"` // first moves made by the Opening
+	Seed              int64 `json:"seed"`    // seed of the choice among the best rivers
 	distanceFromOwned [][]int
 	totalScore        []int64
 }
@@ -13,6 +16,9 @@ type Random0Player struct {
 func (p *Random0Player) MakeMove(moves []Move) Move {
 	p.BaselinePlayer.PrepareForMove(moves)
 
+	if u, v, ok := p.OpeningMove(p.Opening); ok {
+		return p.MakeClaimMove(u, v)
+	}
 	for _, e := range p.AllEdges {
 		if e.Owner < 0 && p.fromMine(e) {
 			return p.MakeClaimMove(e.Src, e.Dst)
@@ -39,7 +45,7 @@ func (p *Random0Player) MakeMove(moves []Move) Move {
 		return p.MakePassMove()
 	}
 
-	r := rand.New(rand.NewSource(42))
+	r := rand.New(rand.NewSource(p.Seed))
 	visited := 0
 	var move Move
 	for i, score := range scores {
@@ -58,6 +64,25 @@ func (p *Random0Player) MakeMove(moves []Move) Move {
 
 func (p *Random0Player) Name() string { return "random0" }
 
+func (p *Random0Player) Params() []Param {
+	return []Param{
+		{Name: "depth", Default: 10, Doc: "how far to look from the new site", Min: 1, Max: 30, Integer: true},
+		{Name: "opening", Default: 0, Doc: "first moves made by the mine-protection opening", Min: 0, Max: 10, Integer: true},
+		seedParam("seed of the choice among the best rivers"),
+	}
+}
+
+func (p *Random0Player) SetParam(name string, value float64) {
+	switch name {
+	case "depth":
+		p.Depth = int(value)
+	case "opening":
+		p.Opening = int(value)
+	case "seed":
+		p.Seed = int64(value)
+	}
+}
+
 func (p *Random0Player) getEdgeScore(e Edge) (score int64) {
 	if e.Owner >= 0 {
 		return
@@ -88,37 +113,10 @@ func (p *Random0Player) getEdgeScore(e Edge) (score int64) {
 	return
 }
 
-func (p *Random0Player) countBonus(u, v, mine int) (bonus int64) {
-	for _, e := range p.Edges[u] {
-		edge := &p.AllEdges[e]
-		if edge.Owner >= 0 {
-			continue
-		}
-		if edge.Dst == v || p.reachableFromMine[mine][edge.Dst] {
-			continue
-		}
-		d := int64(p.Distance[mine][edge.Dst])
-		bonus += d * d
-	}
-	return bonus
-}
-
-func (p *Random0Player) calcDegreesOfFreedom(u int) (d int) {
-	for _, e := range p.Edges[u] {
-		edge := &p.AllEdges[e]
-		if edge.Owner < 0 {
-			d++
-		}
-	}
-	return
-}
-
 func (p *Random0Player) expectedScore(u, mine, depth int, was []int) (score int64) {
-	const depthLimit = 10
-
 	was[u] = mine
-	score += int64(p.Distance[mine][u]) * int64(p.Distance[mine][u])
-	if depth == depthLimit {
+	score += p.SiteScore(mine, u)
+	if depth == p.Depth {
 		return
 	}
 	for _, e := range p.Edges[u] {
@@ -3,12 +3,20 @@ package game
 import (
 	"math"
 	"math/rand"
+	"time"
 )
 
 type Random1Player struct {
 	BaselinePlayer
+	Depth             int     `json:"depth"`    // how far to look from the new site
+	Discount          float64 `json:"discount"` // weight of the sites one river further
+	Opening           int     `json:"opening"`  // first moves made by the Opening
+	Seed              int64   `json:"seed"`     // seed of the choice among the best rivers
+	CurDepth          int     `json:"curDepth"` // Depth reduced to fit in the time budget, 0 if not reduced
+	LastMoveTime      float64 `json:"lastMove"` // seconds taken by the last move
 	distanceFromOwned [][]int
 	totalScore        []int64
+	deadline          deadline
 
 	was   []int64
 	depth []int
@@ -16,8 +24,14 @@ type Random1Player struct {
 }
 
 func (p *Random1Player) MakeMove(moves []Move) Move {
+	start := time.Now()
+	defer func() { p.LastMoveTime = time.Since(start).Seconds() }()
+
 	p.BaselinePlayer.PrepareForMove(moves)
 
+	if u, v, ok := p.OpeningMove(p.Opening); ok {
+		return p.MakeClaimMove(u, v)
+	}
 	for _, e := range p.AllEdges {
 		if e.Owner < 0 && p.fromMine(e) {
 			return p.MakeClaimMove(e.Src, e.Dst)
@@ -37,7 +51,13 @@ func (p *Random1Player) MakeMove(moves []Move) Move {
 
 	scores := make([]int64, len(p.AllEdges))
 	var bestScore int64
+	outOfTime := false
 	for i, e := range p.AllEdges {
+		// Out of time, choose among the edges scored so far.
+		if i%64 == 0 && p.deadline.passed() {
+			outOfTime = true
+			break
+		}
 		scores[i] = p.getEdgeScore(e)
 		if bestScore < scores[i] {
 			bestScore = scores[i]
@@ -45,10 +65,17 @@ func (p *Random1Player) MakeMove(moves []Move) Move {
 	}
 
 	if bestScore == 0 {
+		// Out of time before a river worth it was scored, the greedy
+		// choice is made.
+		if outOfTime {
+			if u, v, ok := p.FindEdge(); ok {
+				return p.MakeClaimMove(u, v)
+			}
+		}
 		return p.MakePassMove()
 	}
 
-	r := rand.New(rand.NewSource(42))
+	r := rand.New(rand.NewSource(p.Seed))
 	visited := 0
 	var move Move
 	for i, score := range scores {
@@ -67,6 +94,60 @@ func (p *Random1Player) MakeMove(moves []Move) Move {
 
 func (p *Random1Player) Name() string { return "random1" }
 
+// Halves the search depth when the last move took more than half of the
+// budget and slowly restores it when moves are fast.
+func (p *Random1Player) SetTimeBudget(budget time.Duration) {
+	p.deadline = deadline(time.Now().Add(budget))
+
+	depth := p.depthLimit()
+	last := time.Duration(p.LastMoveTime * float64(time.Second))
+	switch {
+	case last > budget/2 && depth > 1:
+		depth /= 2
+	case last < budget/8 && depth < p.Depth:
+		depth++
+	}
+	p.CurDepth = depth
+	if depth == p.Depth {
+		p.CurDepth = 0
+	}
+}
+
+func (p *Random1Player) depthLimit() int {
+	if p.CurDepth > 0 && p.CurDepth < p.Depth {
+		return p.CurDepth
+	}
+	return p.Depth
+}
+
+// Falls back to the one river lookahead of the baseline bot.
+func (p *Random1Player) Downgrade() Player {
+	b := p.BaselinePlayer
+	return &b
+}
+
+func (p *Random1Player) Params() []Param {
+	return []Param{
+		{Name: "depth", Default: 10, Doc: "how far to look from the new site", Min: 1, Max: 30, Integer: true},
+		{Name: "discount", Default: 0.95, Doc: "weight of the sites one river further", Min: 0, Max: 1},
+		{Name: "opening", Default: 0, Doc: "first moves made by the mine-protection opening", Min: 0, Max: 10, Integer: true},
+		seedParam("seed of the choice among the best rivers"),
+	}
+}
+
+func (p *Random1Player) SetParam(name string, value float64) {
+	switch name {
+	case "depth":
+		p.Depth = int(value)
+	case "discount":
+		p.Discount = value
+	case "opening":
+		p.Opening = int(value)
+	case "seed":
+		p.Seed = int64(value)
+	}
+}
+
 func (p *Random1Player) getEdgeScore(e Edge) (score int64) {
 	if e.Owner >= 0 {
 		return
@@ -94,35 +175,9 @@ func (p *Random1Player) getEdgeScore(e Edge) (score int64) {
 	return
 }
 
-func (p *Random1Player) countBonus(u, v, mine int) (bonus int64) {
-	for _, e := range p.Edges[u] {
-		edge := &p.AllEdges[e]
-		if edge.Owner >= 0 {
-			continue
-		}
-		if edge.Dst == v || p.reachableFromMine[mine][edge.Dst] {
-			continue
-		}
-		d := int64(p.Distance[mine][edge.Dst])
-		bonus += d * d
-	}
-	return bonus
-}
-
-func (p *Random1Player) calcDegreesOfFreedom(u int) (d int) {
-	for _, e := range p.Edges[u] {
-		edge := &p.AllEdges[e]
-		if edge.Owner < 0 {
-			d++
-		}
-	}
-	return
-}
-
 func (p *Random1Player) expectedScore(u, mine, edgeId int) (score float64) {
-	const depthLimit = 10
-	discount := 0.95
 	mark := int64(len(p.AllEdges))*int64(mine) + int64(edgeId)
+	depthLimit := p.depthLimit()
 
 	qh, qt := 0, 0
 
@@ -135,7 +190,7 @@ func (p *Random1Player) expectedScore(u, mine, edgeId int) (score float64) {
 		u := p.queue[qh]
 		qh++
 
-		score += math.Pow(discount, float64(p.depth[u])) * float64(p.Distance[mine][u]) * float64(p.Distance[mine][u])
+		score += math.Pow(p.Discount, float64(p.depth[u])) * float64(p.SiteScore(mine, u))
 		if p.depth[u] == depthLimit {
 			continue
 		}
@@ -13,19 +13,13 @@ func (p *Random2Player) Name() string {
 	return "random2"
 }
 
+// Bets on futures inside the Voronoi cells of the mines.
 func (p *Random2Player) setupFutures() {
 	if !p.Settings.FuturesMode {
 		return
 	}
 
-	maxU := make([]int, len(p.Mines))
-	maxD := make([]int, len(p.Mines))
-
-	for i := range p.Mines {
-		maxU[i] = -1
-		maxD[i] = -1
-	}
-
+	cell := make([]int, p.NumSites)
 	for u := 0; u < p.NumSites; u++ {
 		bestI := -1
 		bestD := p.NumSites
@@ -39,18 +33,12 @@ func (p *Random2Player) setupFutures() {
 				bestD = d
 			}
 		}
-
-		if bestI >= 0 {
-			if maxD[bestI] < bestD {
-				maxU[bestI] = u
-				maxD[bestI] = bestD
-			}
-		}
+		cell[u] = bestI
 	}
 
-	for i, m := range p.Mines {
-		if maxU[i] >= 0 {
-			p.Futures = append(p.Futures, Future{Src: m, Dst: maxU[i]})
-		}
+	planner := MakeFuturePlanner(&p.Graph, p.Punters)
+	inCell := func(i, v int) bool { return cell[v] == i }
+	for _, fe := range planner.ChooseFutures(inCell) {
+		p.Futures = append(p.Futures, fe.Future)
 	}
 }
@@ -0,0 +1,117 @@
+package game
+
+// Scorer computes the official score of a single punter: the squared
+// distances from every mine to the sites connected to it by the rivers
+// the punter owns or holds an option on, plus the bonus or penalty for
+// every future.
+type Scorer struct {
+	g       *Graph
+	punter  int
+	futures []Future
+
+	comp      []int     // comp[v] is the component of site v over the punter's rivers
+	mineScore [][]int64 // mineScore[i][c] is the score for mine i of the sites in component c
+}
+
+func MakeScorer(g *Graph, punter int, futures []Future) (s Scorer) {
+	s.g = g
+	s.punter = punter
+	s.futures = futures
+
+	s.comp = make([]int, g.NumSites)
+	for v := range s.comp {
+		s.comp[v] = -1
+	}
+	n := 0
+	for v := range s.comp {
+		if s.comp[v] < 0 {
+			g.markComponent(v, punter, n, s.comp)
+			n++
+		}
+	}
+
+	s.mineScore = make([][]int64, len(g.Mines))
+	for i := range g.Mines {
+		s.mineScore[i] = make([]int64, n)
+		for v, c := range s.comp {
+			s.mineScore[i][c] += g.SiteScore(i, v)
+		}
+	}
+	return
+}
+
+func (g *Graph) markComponent(u, punter, c int, comp []int) {
+	comp[u] = c
+	for _, eId := range g.Edges[u] {
+		e := &g.AllEdges[eId]
+		if e.UsableBy(punter) && comp[e.Dst] < 0 {
+			g.markComponent(e.Dst, punter, c, comp)
+		}
+	}
+}
+
+// Returns the component of site v over the punter's rivers, an int from
+// the range [0..NumSites).
+func (s *Scorer) Component(v int) int {
+	return s.comp[v]
+}
+
+// Returns true if sites u and v are connected by the punter's rivers.
+func (s *Scorer) Connected(u, v int) bool {
+	return s.comp[u] == s.comp[v]
+}
+
+// Returns the score without futures.
+func (s *Scorer) MinesScore() (score int64) {
+	for i, m := range s.g.Mines {
+		score += s.mineScore[i][s.comp[m]]
+	}
+	return
+}
+
+// Returns d^3 for a satisfied future and -d^3 for a failed one. Futures
+// to sites unreachable from the mine are worth nothing.
+func (s *Scorer) FutureScore(f Future) int64 {
+	i := s.g.MineIndex(f.Src)
+	if i < 0 || s.g.Distance[i][f.Dst] < 0 {
+		return 0
+	}
+	d := int64(s.g.Distance[i][f.Dst])
+	if s.Connected(f.Src, f.Dst) {
+		return d * d * d
+	}
+	return -d * d * d
+}
+
+func (s *Scorer) Score() int64 {
+	score := s.MinesScore()
+	for _, f := range s.futures {
+		score += s.FutureScore(f)
+	}
+	return score
+}
+
+// Returns the change of the score if the punter claimed the river (u, v).
+func (s *Scorer) ClaimGain(u, v int) (gain int64) {
+	cu, cv := s.comp[u], s.comp[v]
+	if cu == cv {
+		return
+	}
+
+	for i, m := range s.g.Mines {
+		switch s.comp[m] {
+		case cu:
+			gain += s.mineScore[i][cv]
+		case cv:
+			gain += s.mineScore[i][cu]
+		}
+	}
+
+	for _, f := range s.futures {
+		a, b := s.comp[f.Src], s.comp[f.Dst]
+		if a != b && (a == cu && b == cv || a == cv && b == cu) {
+			gain -= 2 * s.FutureScore(f)
+		}
+	}
+	return
+}
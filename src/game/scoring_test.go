@@ -0,0 +1,65 @@
+package game
+
+import "testing"
+
+func TestScorerClaimGain(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+	g.SetEdgeOwnership(1, 2, 0)
+	g.SetEdgeOwnership(4, 6, 0)
+	g.SetEdgeOwnership(5, 6, 1)
+	futures := []Future{{Src: 0, Dst: 3}, {Src: 4, Dst: 5}}
+
+	before := MakeScorer(&g, 0, futures)
+	for _, e := range g.AllEdges {
+		if e.Owner >= 0 {
+			continue
+		}
+		g.SetEdgeOwnership(e.Src, e.Dst, 0)
+		after := MakeScorer(&g, 0, futures)
+		if gain := before.ClaimGain(e.Src, e.Dst); gain != after.Score()-before.Score() {
+			t.Errorf("claim of (%v, %v): got gain %v, want %v", e.Src, e.Dst, gain, after.Score()-before.Score())
+		}
+		g.AllEdges[e.Id].Owner = -1
+		g.AllEdges[e.Id^1].Owner = -1
+	}
+}
+
+func TestScorerFutures(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+	g.SetEdgeOwnership(0, 1, 0)
+	g.SetEdgeOwnership(1, 2, 0)
+	g.AllEdges[6].Option = 0 // (4, 5)
+
+	s := MakeScorer(&g, 0, []Future{{Src: 0, Dst: 2}, {Src: 4, Dst: 6}, {Src: 7, Dst: 0}})
+	if s.MinesScore() != 5+1 {
+		t.Errorf("mines score: got %v, want 6", s.MinesScore())
+	}
+	if s.Score() != 6+8-1 {
+		t.Errorf("score: got %v, want 13", s.Score())
+	}
+}
+
+func TestFutureProgress(t *testing.T) {
+	var p BaselinePlayer
+	p.Setup(0, 2, disconnectedMap(), Settings{FuturesMode: true})
+	p.Futures = []Future{{Src: 0, Dst: 3}}
+	p.PrepareForMove(nil)
+
+	if p.score != -27 {
+		t.Errorf("score with a failed future: got %v, want -27", p.score)
+	}
+	// One step of three towards the target of the future worth 2*27.
+	if g := p.FutureProgress(0, 1); g != 18 {
+		t.Errorf("progress of (0, 1): got %v, want 18", g)
+	}
+	if g := p.FutureProgress(1, 2); g != 0 {
+		t.Errorf("progress of (1, 2): got %v, want 0", g)
+	}
+
+	p.PrepareForMove([]Move{MakeClaimMove(0, 0, 1), MakeClaimMove(1, 1, 2)})
+	if g := p.FutureProgress(1, 2); g != 0 {
+		t.Errorf("progress of a claimed river: got %v, want 0", g)
+	}
+}
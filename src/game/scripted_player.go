@@ -0,0 +1,67 @@
+package game
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// The prefix of the names of scripted players, e.g.
+// "scripted:claim 1 2;pass;splurge 2 3 4" or "scripted:@moves.txt".
+const ScriptedPrefix = "scripted:"
+
+// ScriptedPlayer replays a fixed list of moves, written as for the
+// human player with the map's site ids. It passes once the list runs out
+// and panics on an illegal move, which makes it a tool for exact tests
+// of the engine.
+type ScriptedPlayer struct {
+	BaselinePlayer
+	SiteIds
+
+	Script []string `json:"script"`
+	Next   int      `json:"next"` // index of the next move in the script
+}
+
+// Splits the script into moves, separated by semicolons or newlines.
+// Empty lines and lines starting with # are skipped.
+func ParseScript(script string) (moves []string) {
+	for _, line := range strings.Split(strings.Replace(script, "\n", ";", -1), ";") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		moves = append(moves, line)
+	}
+	return
+}
+
+// Makes a scripted player from the part of the name after the prefix:
+// either the script itself or @ and the path to the file with it.
+func MakeScriptedPlayer(spec string) (*ScriptedPlayer, error) {
+	script := spec
+	if strings.HasPrefix(spec, "@") {
+		bs, err := ioutil.ReadFile(spec[1:])
+		if err != nil {
+			return nil, fmt.Errorf("can't read script: %v", err)
+		}
+		script = string(bs)
+	}
+	return &ScriptedPlayer{Script: ParseScript(script)}, nil
+}
+
+func (p *ScriptedPlayer) Name() string { return "scripted" }
+
+func (p *ScriptedPlayer) MakeMove(moves []Move) Move {
+	p.PrepareForMove(moves)
+	if p.Next >= len(p.Script) {
+		return p.MakePassMove()
+	}
+
+	line := p.Script[p.Next]
+	p.Next++
+	m, err := p.ParseTextMove(line, p.site)
+	if err != nil {
+		panic(fmt.Sprintf("punter %v: bad scripted move %v %q: %v", p.Punter, p.Next, line, err))
+	}
+	return p.makeTextMove(m)
+}
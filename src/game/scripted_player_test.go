@@ -0,0 +1,56 @@
+package game
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	got := ParseScript("claim 1 2; pass\n# a comment\n\n  splurge 2 3 4 ;")
+	want := []string{"claim 1 2", "pass", "splurge 2 3 4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestScriptedPlayer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scripted")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "moves.txt")
+	if err := ioutil.WriteFile(path, []byte("0 1\npass\n1 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := MakePlayer(ScriptedPrefix + "@" + path)
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	if m := p.MakeMove(nil); m.Type != Claim || m.Source != 0 || m.Target != 1 {
+		t.Errorf("first move: got %v, want claim of (0, 1)", m)
+	}
+	if m := p.MakeMove(nil); m.Type != Pass {
+		t.Errorf("second move: got %v, want pass", m)
+	}
+
+	// Punter 1 takes the river the script wants.
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("illegal move: no panic")
+			}
+		}()
+		p.MakeMove([]Move{MakePassMove(0), MakeClaimMove(1, 1, 2)})
+	}()
+
+	if m := p.MakeMove(nil); m.Type != Pass {
+		t.Errorf("move after the script: got %v, want pass", m)
+	}
+
+	if _, err := MakePlayerWithParams(ScriptedPrefix+"@"+filepath.Join(dir, "none"), nil); err == nil {
+		t.Error("missing script: no error")
+	}
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Selector picks the river to claim among the candidates, using the
+// evaluator that is prepared for the current position.
+type Selector interface {
+	Select(p *BaselinePlayer, ev Evaluator, rivers []*Edge, r *rand.Rand) *Edge
+}
+
+// Returns the parameters of the selector with the given name.
+func selectorParams(name string) []Param {
+	switch name {
+	case "softmax":
+		return []Param{
+			{Name: "temperature", Default: 1, Doc: "score difference that makes a river e times less likely", Min: 0, Max: 1000},
+		}
+	case "topk":
+		return []Param{
+			{Name: "k", Default: 5, Doc: "best rivers to look ahead from", Min: 1, Max: 30, Integer: true},
+			{Name: "discount", Default: 0.5, Doc: "weight of the best next river", Min: 0, Max: 1},
+		}
+	}
+	return nil
+}
+
+func newSelector(name string, values map[string]float64) (Selector, error) {
+	switch name {
+	case "argmax":
+		return argmaxSelector{}, nil
+	case "softmax":
+		return softmaxSelector{Temperature: values["temperature"]}, nil
+	case "topk":
+		return topKSelector{K: int(values["k"]), Discount: values["discount"]}, nil
+	}
+	return nil, fmt.Errorf("unknown selector: %v", name)
+}
+
+// Takes the best river, a random one of the best on ties.
+type argmaxSelector struct{}
+
+func (argmaxSelector) Select(p *BaselinePlayer, ev Evaluator, rivers []*Edge, r *rand.Rand) (best *Edge) {
+	var bestScore float64
+	ties := 0
+	for _, e := range rivers {
+		s := ev.Score(p, e)
+		switch {
+		case best == nil || s > bestScore:
+			best, bestScore, ties = e, s, 1
+		case s == bestScore:
+			ties++
+			if r.Intn(ties) == 0 {
+				best = e
+			}
+		}
+	}
+	return
+}
+
+// Takes a river with the chance proportional to exp(score/Temperature),
+// the best one with zero temperature.
+type softmaxSelector struct {
+	Temperature float64
+}
+
+func (s softmaxSelector) Select(p *BaselinePlayer, ev Evaluator, rivers []*Edge, r *rand.Rand) *Edge {
+	if s.Temperature <= 0 || len(rivers) == 0 {
+		return argmaxSelector{}.Select(p, ev, rivers, r)
+	}
+	scores := make([]float64, len(rivers))
+	max := math.Inf(-1)
+	for i, e := range rivers {
+		scores[i] = ev.Score(p, e)
+		max = math.Max(max, scores[i])
+	}
+	sum := 0.0
+	for i := range scores {
+		scores[i] = math.Exp((scores[i] - max) / s.Temperature)
+		sum += scores[i]
+	}
+	x := r.Float64() * sum
+	for i, w := range scores {
+		if x -= w; x < 0 {
+			return rivers[i]
+		}
+	}
+	return rivers[len(rivers)-1]
+}
+
+// Takes the best of the K best rivers, counting the best river the punter
+// could claim next, with the others' moves ignored.
+type topKSelector struct {
+	K        int
+	Discount float64
+}
+
+func (s topKSelector) Select(p *BaselinePlayer, ev Evaluator, rivers []*Edge, r *rand.Rand) (best *Edge) {
+	rivers = append([]*Edge(nil), rivers...)
+	scores := rankRivers(p, ev, rivers)
+	k := s.K
+	if k < 1 {
+		k = 1
+	}
+	if len(rivers) > k {
+		rivers = rivers[:k]
+	}
+
+	var bestScore float64
+	for i, e := range rivers {
+		e.Owner, p.AllEdges[e.Id^1].Owner = p.Punter, p.Punter
+		p.recalc(ev)
+		next := 0.0
+		for j, n := range p.FreeRiversNearby() {
+			if score := ev.Score(p, n); j == 0 || score > next {
+				next = score
+			}
+		}
+		e.Owner, p.AllEdges[e.Id^1].Owner = -1, -1
+
+		if score := scores[i] + s.Discount*next; best == nil || score > bestScore {
+			best, bestScore = e, score
+		}
+	}
+	p.recalc(ev)
+	return
+}
+
+// Updates everything PrepareForMove computes after the graph was changed.
+func (p *BaselinePlayer) recalc(ev Evaluator) {
+	p.CalcReachabilityFromMines()
+	p.CalcScores()
+	p.CalcFutureDistances()
+	ev.Prepare(p)
+}
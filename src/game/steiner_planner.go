@@ -0,0 +1,233 @@
+package game
+
+import "sort"
+
+// SteinerLink is a path of rivers that brings a mine into the tree of
+// the punter's network.
+type SteinerLink struct {
+	Mine   int   // index of the mine in Graph.Mines
+	Attach int   // the site of the tree where the link starts
+	Free   []int // the free rivers (edge ids) on the path, from the tree to the mine
+	Detour int   // how many more free rivers an alternative path takes, -1 if there is none
+}
+
+// SteinerPlanner links the mines with the punter's rivers along an
+// approximate Steiner tree: starting from one mine, it adds the mine
+// nearest to the tree over the free and own rivers, and so on while the
+// links are short enough.
+type SteinerPlanner struct {
+	g       *Graph
+	punter  int
+	maxLink int // the longest link, in free rivers
+}
+
+func MakeSteinerPlanner(g *Graph, punter, maxLink int) SteinerPlanner {
+	return SteinerPlanner{g: g, punter: punter, maxLink: maxLink}
+}
+
+// Returns the cost of walking the edge: 0 for the punter's rivers, 1 for
+// free ones and -1 for the rivers of the others and the banned ones.
+func (sp *SteinerPlanner) cost(e *Edge, banned []bool) int {
+	switch {
+	case banned != nil && banned[e.Id/2]:
+		return -1
+	case e.Owner < 0:
+		return 1
+	case e.UsableBy(sp.punter):
+		return 0
+	}
+	return -1
+}
+
+// 0-1 BFS from the sources. Returns the number of free rivers to every
+// site, -1 if unreachable, and the edge the site is reached by.
+func (sp *SteinerPlanner) distances(sources []bool, banned []bool) (dist []int, prev []int) {
+	g := sp.g
+	dist = make([]int, g.NumSites)
+	prev = make([]int, g.NumSites)
+	deque := make([]int, 0, 2*g.NumSites)
+	for v := range dist {
+		dist[v] = -1
+		prev[v] = -1
+		if sources[v] {
+			dist[v] = 0
+			deque = append(deque, v)
+		}
+	}
+
+	// The deque is a slice with the front moving right; sites reached by
+	// free rivers go to the back, the others go to the front.
+	var front []int
+	for len(deque) > 0 || len(front) > 0 {
+		var u int
+		if n := len(front); n > 0 {
+			u, front = front[n-1], front[:n-1]
+		} else {
+			u, deque = deque[0], deque[1:]
+		}
+		for _, eId := range g.Edges[u] {
+			e := &g.AllEdges[eId]
+			c := sp.cost(e, banned)
+			if c < 0 {
+				continue
+			}
+			if d := dist[e.Dst]; d >= 0 && d <= dist[u]+c {
+				continue
+			}
+			dist[e.Dst] = dist[u] + c
+			prev[e.Dst] = eId
+			if c == 0 {
+				front = append(front, e.Dst)
+			} else {
+				deque = append(deque, e.Dst)
+			}
+		}
+	}
+	return
+}
+
+// Chooses the mine to start the tree from: the one with most other mines
+// within maxLink rivers.
+func (sp *SteinerPlanner) root() int {
+	g := sp.g
+	best, bestNear := -1, -1
+	for i := range g.Mines {
+		near := 0
+		for _, m := range g.Mines {
+			if d := g.Distance[i][m]; d > 0 && d <= sp.maxLink {
+				near++
+			}
+		}
+		if near > bestNear {
+			best, bestNear = i, near
+		}
+	}
+	return best
+}
+
+// Builds the tree and returns its links in the order they were added.
+// The links are recomputed from the current state of the map, so the
+// rivers claimed by the others make the plan go around them.
+func (sp *SteinerPlanner) Plan() (links []SteinerLink) {
+	g := sp.g
+	root := sp.root()
+	if root < 0 {
+		return nil
+	}
+
+	inTree := make([]bool, g.NumSites)
+	inTree[g.Mines[root]] = true
+	linked := make([]bool, len(g.Mines))
+	linked[root] = true
+	for {
+		dist, prev := sp.distances(inTree, nil)
+
+		next := -1
+		for i, m := range g.Mines {
+			if !linked[i] && dist[m] >= 0 && dist[m] <= sp.maxLink && (next < 0 || dist[m] < dist[g.Mines[next]]) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return
+		}
+
+		link := SteinerLink{Mine: next}
+		v := g.Mines[next]
+		for !inTree[v] {
+			e := &g.AllEdges[prev[v]]
+			if e.Owner < 0 {
+				link.Free = append(link.Free, e.Id)
+			}
+			v = e.Src
+		}
+		link.Attach = v
+		for i, j := 0, len(link.Free)-1; i < j; i, j = i+1, j-1 {
+			link.Free[i], link.Free[j] = link.Free[j], link.Free[i]
+		}
+		link.Detour = sp.detour(&link, inTree)
+
+		// All sites of the path join the tree, not only the mine.
+		for v := g.Mines[next]; !inTree[v]; v = g.AllEdges[prev[v]].Src {
+			inTree[v] = true
+		}
+		linked[next] = true
+		links = append(links, link)
+	}
+}
+
+// Returns how many more free rivers the link takes if its free rivers
+// are claimed by the others, -1 if the mine can't be linked then.
+func (sp *SteinerPlanner) detour(link *SteinerLink, inTree []bool) int {
+	if len(link.Free) == 0 {
+		return 0
+	}
+	banned := make([]bool, len(sp.g.AllEdges)/2)
+	for _, eId := range link.Free {
+		banned[eId/2] = true
+	}
+	dist, _ := sp.distances(inTree, banned)
+	if d := dist[sp.g.Mines[link.Mine]]; d >= 0 {
+		return d - len(link.Free)
+	}
+	return -1
+}
+
+// Returns the links that still need rivers, the most contested first:
+// the ones without an alternative, then by the length of the detour and
+// then the shortest ones.
+func (sp *SteinerPlanner) Contested(links []SteinerLink) []SteinerLink {
+	var open []SteinerLink
+	for _, l := range links {
+		if len(l.Free) > 0 {
+			open = append(open, l)
+		}
+	}
+	detour := func(l *SteinerLink) int {
+		if l.Detour < 0 {
+			return sp.g.NumSites
+		}
+		return l.Detour
+	}
+	sort.SliceStable(open, func(a, b int) bool {
+		da, db := detour(&open[a]), detour(&open[b])
+		if da != db {
+			return da > db
+		}
+		return len(open[a].Free) < len(open[b].Free)
+	})
+	return open
+}
+
+// Returns the river to claim next: on the most contested link, the free
+// river between the sites with the fewest free rivers around, where the
+// others are most likely to cut the link.
+func (sp *SteinerPlanner) NextRiver() (u, v int, ok bool) {
+	open := sp.Contested(sp.Plan())
+	if len(open) == 0 {
+		return
+	}
+
+	g := sp.g
+	freeDegree := func(v int) (n int) {
+		for _, eId := range g.Edges[v] {
+			if g.AllEdges[eId].Owner < 0 {
+				n++
+			}
+		}
+		return
+	}
+	best := -1
+	for _, eId := range open[0].Free {
+		e := &g.AllEdges[eId]
+		d := freeDegree(e.Src)
+		if dd := freeDegree(e.Dst); dd < d {
+			d = dd
+		}
+		if best < 0 || d < best {
+			best = d
+			u, v = e.Src, e.Dst
+		}
+	}
+	return u, v, true
+}
@@ -0,0 +1,40 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSteinerPlanner(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+	sp := MakeSteinerPlanner(&g, 0, 6)
+
+	// Mines 4 and 6 are next to each other, mine 0 is in another component
+	// and mine 7 is alone.
+	links := sp.Plan()
+	want := []SteinerLink{{Mine: 2, Attach: 4, Free: []int{10}, Detour: 1}}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("plan: got %+v, want %+v", links, want)
+	}
+	if u, v, ok := sp.NextRiver(); !ok || u != 4 || v != 6 {
+		t.Errorf("next river: got (%v, %v), want (4, 6)", u, v)
+	}
+
+	// Punter 1 blocks the short link, the plan goes around it.
+	g.SetEdgeOwnership(4, 6, 1)
+	want = []SteinerLink{{Mine: 2, Attach: 4, Free: []int{6, 8}, Detour: -1}}
+	if links := sp.Plan(); !reflect.DeepEqual(links, want) {
+		t.Errorf("plan after the block: got %+v, want %+v", links, want)
+	}
+
+	// Our own river costs nothing.
+	g.SetEdgeOwnership(4, 5, 0)
+	if open := sp.Contested(sp.Plan()); len(open) != 1 || !reflect.DeepEqual(open[0].Free, []int{8}) {
+		t.Errorf("contested links: got %+v, want the river (5, 6)", open)
+	}
+	g.SetEdgeOwnership(5, 6, 0)
+	if _, _, ok := sp.NextRiver(); ok {
+		t.Error("next river: the mines are linked, but got a river")
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+// SteinerPlayer links the mines along an approximate Steiner tree,
+// securing the most contested links first, and plays like the m bot
+// when there is nothing left to link.
+type SteinerPlayer struct {
+	MPlayer
+	MaxLink int `json:"maxLink"` // the longest link between mines, in free rivers
+}
+
+func (p *SteinerPlayer) Name() string {
+	return "steiner"
+}
+
+func (p *SteinerPlayer) Params() []Param {
+	return append(p.MPlayer.Params(),
+		Param{Name: "maxLink", Default: 6, Doc: "the longest link between mines, in free rivers", Min: 1, Max: 30, Integer: true})
+}
+
+func (p *SteinerPlayer) SetParam(name string, value float64) {
+	switch name {
+	case "maxLink":
+		p.MaxLink = int(value)
+	default:
+		p.MPlayer.SetParam(name, value)
+	}
+}
+
+// Links longer than the number of our remaining moves are not worth it.
+func (p *SteinerPlayer) maxLink() int {
+	free := 0
+	for _, e := range p.AllEdges {
+		if e.Owner < 0 {
+			free++
+		}
+	}
+	moves := (free/2 + p.Punters - 1) / p.Punters
+	if moves < p.MaxLink {
+		return moves
+	}
+	return p.MaxLink
+}
+
+func (p *SteinerPlayer) MakeMove(moves []Move) Move {
+	p.PrepareForMove(moves)
+
+	planner := MakeSteinerPlanner(&p.Graph, p.Punter, p.maxLink())
+	if u, v, ok := planner.NextRiver(); ok {
+		return p.MakeClaimMove(u, v)
+	}
+
+	u, v, ok := p.FindEdge()
+	if !ok {
+		return p.MakePassMove()
+	}
+	return p.MakeClaimMove(u, v)
+}
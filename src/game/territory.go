@@ -0,0 +1,132 @@
+package game
+
+// Territory splits the sites between the punters like Voronoi cells: a
+// site belongs to the punter whose network reaches it over the fewest
+// free rivers. Sites as near to several punters belong to none of them.
+// The potential of a punter is the score it would get if it claimed its
+// whole territory, futures aside.
+type Territory struct {
+	g *Graph
+
+	Dist      [][]int // Dist[p][v] is the number of free rivers punter p needs to reach site v, or -1
+	Owner     []int   // Owner[v] is the punter whose territory site v is in, or -1
+	Potential []int64 // Potential[p] is the score of punter p over its network and territory
+}
+
+// Returns the sites touched by the punter's rivers. A punter without
+// rivers can start at any mine.
+func (g *Graph) network(punter int) []bool {
+	was := make([]bool, g.NumSites)
+	empty := true
+	for _, e := range g.AllEdges {
+		if e.UsableBy(punter) {
+			was[e.Src] = true
+			empty = false
+		}
+	}
+	if empty {
+		for _, m := range g.Mines {
+			was[m] = true
+		}
+	}
+	return was
+}
+
+func MakeTerritory(g *Graph, punters int) (t Territory) {
+	t.g = g
+	t.Dist = make([][]int, punters)
+	for p := range t.Dist {
+		t.Dist[p] = g.FreeMSSP(g.network(p), p)
+	}
+
+	t.Owner = make([]int, g.NumSites)
+	for v := range t.Owner {
+		t.Owner[v] = -1
+		best := -1
+		for p := range t.Dist {
+			d := t.Dist[p][v]
+			switch {
+			case d < 0:
+			case best < 0 || d < best:
+				best = d
+				t.Owner[v] = p
+			case d == best:
+				t.Owner[v] = -1
+			}
+		}
+	}
+
+	t.Potential = make([]int64, punters)
+	for p := range t.Potential {
+		t.Potential[p] = t.potential(p)
+	}
+	return
+}
+
+// Sites of the punter's network are in its region even when they are as
+// near to the others.
+func (t *Territory) inRegion(p, v int) bool {
+	return t.Dist[p][v] == 0 || t.Owner[v] == p
+}
+
+// Scores the components of the punter's region over the rivers that are
+// free or usable by the punter.
+func (t *Territory) potential(p int) (score int64) {
+	g := t.g
+	comp := make([]int, g.NumSites)
+	for v := range comp {
+		comp[v] = -1
+	}
+	stack := make([]int, 0, g.NumSites)
+	n := 0
+	for s := range comp {
+		if comp[s] >= 0 || !t.inRegion(p, s) {
+			continue
+		}
+		comp[s] = n
+		stack = append(stack[:0], s)
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, eId := range g.Edges[u] {
+				e := &g.AllEdges[eId]
+				if e.Owner >= 0 && !e.UsableBy(p) {
+					continue
+				}
+				if comp[e.Dst] < 0 && t.inRegion(p, e.Dst) {
+					comp[e.Dst] = n
+					stack = append(stack, e.Dst)
+				}
+			}
+		}
+		n++
+	}
+
+	for i, m := range g.Mines {
+		c := comp[m]
+		if c < 0 {
+			continue
+		}
+		for v := range comp {
+			if comp[v] == c {
+				score += g.SiteScore(i, v)
+			}
+		}
+	}
+	return
+}
+
+// Returns the potential of the punter less the best potential of the
+// others, the evaluation of the position for the punter.
+func (t *Territory) Margin(punter int) int64 {
+	margin := t.Potential[punter]
+	best := int64(0)
+	first := true
+	for p, s := range t.Potential {
+		if p != punter && (first || s > best) {
+			best = s
+			first = false
+		}
+	}
+	return margin - best
+}
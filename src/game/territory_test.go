@@ -0,0 +1,43 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTerritory(t *testing.T) {
+	var g Graph
+	g.InitGraph(disconnectedMap())
+
+	// Nobody has rivers yet, everyone starts at the mines.
+	tr := MakeTerritory(&g, 2)
+	if want := []int64{2, 2}; !reflect.DeepEqual(tr.Potential, want) {
+		t.Errorf("initial potential: got %v, want %v", tr.Potential, want)
+	}
+	if m := tr.Margin(0); m != 0 {
+		t.Errorf("initial margin: got %v, want 0", m)
+	}
+
+	// Punter 0 takes the whole component of mine 0, punter 1 can't get
+	// there any more.
+	g.SetEdgeOwnership(0, 1, 0)
+	tr = MakeTerritory(&g, 2)
+	if want := []int{-1, 0, 0, 0, 1, 1, 1, 1}; !reflect.DeepEqual(tr.Owner, want) {
+		t.Errorf("owners: got %v, want %v", tr.Owner, want)
+	}
+	if want := []int64{14, 4}; !reflect.DeepEqual(tr.Potential, want) {
+		t.Errorf("potential: got %v, want %v", tr.Potential, want)
+	}
+	if m := tr.Margin(0); m != 10 {
+		t.Errorf("margin: got %v, want 10", m)
+	}
+}
+
+func TestVoronoiPlayer(t *testing.T) {
+	p := MakePlayer("voronoi")
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	m := p.MakeMove(nil)
+	if m.Type != Claim || m.Source != 0 || m.Target != 1 {
+		t.Errorf("got %+v, want a claim of (0, 1)", m)
+	}
+}
@@ -0,0 +1,177 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SiteIdsAware players get the ids of the sites from the map, to talk
+// to humans in terms of the map instead of the compressed format.
+type SiteIdsAware interface {
+	SetSiteIds(ids []int)
+}
+
+// SiteIds converts the sites between the compressed format and the map.
+// Without the ids from the map the sites are shown as they are.
+type SiteIds struct {
+	Ids []int `json:"ids"` // Ids[v] is the map's id of site v
+}
+
+func (s *SiteIds) SetSiteIds(ids []int) {
+	s.Ids = ids
+}
+
+func (s *SiteIds) siteId(v int) int {
+	if v < len(s.Ids) {
+		return s.Ids[v]
+	}
+	return v
+}
+
+func (s *SiteIds) site(id string) (int, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("not a site id: %q", id)
+	}
+	if s.Ids == nil {
+		return n, nil
+	}
+	for v, siteId := range s.Ids {
+		if siteId == n {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("no site %v", n)
+}
+
+// Returns the free edge between sites u and v, nil if there is none.
+func (p *BaselinePlayer) freeEdge(u, v int) *Edge {
+	if u < 0 || u >= p.NumSites {
+		return nil
+	}
+	for _, eId := range p.Edges[u] {
+		e := &p.AllEdges[eId]
+		if e.Dst == v && e.Owner < 0 {
+			return e
+		}
+	}
+	return nil
+}
+
+// Returns how many options the player has bought.
+func (p *BaselinePlayer) optionsBought() int {
+	n := 0
+	for _, e := range p.AllEdges {
+		if e.Option == p.Punter && e.Src < e.Dst {
+			n++
+		}
+	}
+	return n
+}
+
+// An option can be bought on a river claimed by someone else, once per
+// river.
+func (p *BaselinePlayer) optionEdge(u, v int) *Edge {
+	if u < 0 || u >= p.NumSites {
+		return nil
+	}
+	for _, eId := range p.Edges[u] {
+		e := &p.AllEdges[eId]
+		if e.Dst == v && e.Owner >= 0 && e.Owner != p.Punter && e.Option < 0 {
+			return e
+		}
+	}
+	return nil
+}
+
+// Parses and checks a move written as "claim a b" (or just "a b"),
+// "pass", "splurge a b c..." or "option a b". The sites are converted
+// with the site function and errors name them as they were written. The
+// move is not made, see makeTextMove.
+func (p *BaselinePlayer) ParseTextMove(line string, site func(string) (int, error)) (m Move, err error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return m, fmt.Errorf("empty move")
+	}
+
+	cmd := fields[0]
+	if _, err := strconv.Atoi(cmd); err == nil {
+		cmd = "claim"
+	} else {
+		fields = fields[1:]
+	}
+
+	sites := make([]int, len(fields))
+	for i, f := range fields {
+		if sites[i], err = site(f); err != nil {
+			return
+		}
+	}
+
+	switch cmd {
+	case "pass":
+		if len(sites) != 0 {
+			return m, fmt.Errorf("pass takes no sites")
+		}
+		return MakePassMove(p.Punter), nil
+
+	case "claim":
+		if len(sites) != 2 {
+			return m, fmt.Errorf("claim takes two sites")
+		}
+		if p.freeEdge(sites[0], sites[1]) == nil {
+			return m, fmt.Errorf("no free river between %v and %v", fields[0], fields[1])
+		}
+		return MakeClaimMove(p.Punter, sites[0], sites[1]), nil
+
+	case "splurge":
+		if !p.Settings.SplurgesMode {
+			return m, fmt.Errorf("splurges are off")
+		}
+		if len(sites) < 2 {
+			return m, fmt.Errorf("splurge takes at least two sites")
+		}
+		if len(sites)-1 > p.Passes+1 {
+			return m, fmt.Errorf("%v rivers need %v passes, you have %v", len(sites)-1, len(sites)-2, p.Passes)
+		}
+		used := make(map[int]bool)
+		for i := 0; i+1 < len(sites); i++ {
+			e := p.freeEdge(sites[i], sites[i+1])
+			if e == nil || used[e.Id/2] {
+				return m, fmt.Errorf("no free river between %v and %v", fields[i], fields[i+1])
+			}
+			used[e.Id/2] = true
+		}
+		return MakeSplurgeMove(p.Punter, sites), nil
+
+	case "option":
+		if !p.Settings.OptionsMode {
+			return m, fmt.Errorf("options are off")
+		}
+		if len(sites) != 2 {
+			return m, fmt.Errorf("option takes two sites")
+		}
+		if p.optionsBought() >= len(p.Mines) {
+			return m, fmt.Errorf("all %v options are bought", len(p.Mines))
+		}
+		if p.optionEdge(sites[0], sites[1]) == nil {
+			return m, fmt.Errorf("no river between %v and %v to buy an option on", fields[0], fields[1])
+		}
+		return MakeOptionMove(p.Punter, sites[0], sites[1]), nil
+	}
+	return m, fmt.Errorf("unknown command %q", cmd)
+}
+
+// Makes the parsed move, updating the number of passes.
+func (p *BaselinePlayer) makeTextMove(m Move) Move {
+	switch m.Type {
+	case Claim:
+		return p.MakeClaimMove(m.Source, m.Target)
+	case Splurge:
+		return p.MakeSplurgeMove(m.Route)
+	case Option:
+		return p.MakeOptionMove(m.Source, m.Target)
+	}
+	return p.MakePassMove()
+}
@@ -0,0 +1,24 @@
+package game
+
+import "time"
+
+// TimeLimited players adapt the amount of work to the time they have for
+// a move. Players that are not TimeLimited ignore the budget.
+type TimeLimited interface {
+	SetTimeBudget(budget time.Duration)
+}
+
+// Downgradable players can be replaced with a cheaper bot that keeps
+// their state, for when even the reduced work doesn't fit in time.
+type Downgradable interface {
+	Downgrade() Player
+}
+
+// A budget is not set in the playground, so the bots may take as long
+// as they need there.
+type deadline time.Time
+
+func (d deadline) passed() bool {
+	t := time.Time(d)
+	return !t.IsZero() && time.Now().After(t)
+}
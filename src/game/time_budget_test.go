@@ -0,0 +1,42 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRandom1TimeBudget(t *testing.T) {
+	p := MakePlayer("random1").(*Random1Player)
+
+	p.LastMoveTime = 0.6
+	p.SetTimeBudget(time.Second)
+	if p.depthLimit() != 5 {
+		t.Errorf("depth after a slow move: got %v, want 5", p.depthLimit())
+	}
+
+	p.LastMoveTime = 0.01
+	p.SetTimeBudget(time.Second)
+	if p.depthLimit() != 6 {
+		t.Errorf("depth after a fast move: got %v, want 6", p.depthLimit())
+	}
+
+	p.Setup(0, 2, disconnectedMap(), Settings{})
+	moves := []Move{
+		MakeClaimMove(0, 0, 1),
+		MakeClaimMove(1, 4, 5),
+		MakeClaimMove(1, 5, 6),
+		MakeClaimMove(1, 4, 6),
+	}
+
+	// Out of time before any river is scored, the greedy choice.
+	p.SetTimeBudget(-time.Second)
+	m := p.MakeMove(moves)
+	if u, v, _ := p.FindEdge(); m.Type != Claim || m.Source != u || m.Target != v {
+		t.Errorf("move out of time: got %v, want claim of (%v, %v)", m, u, v)
+	}
+
+	p.SetTimeBudget(time.Second)
+	if m := p.MakeMove(nil); m.Type != Claim || m.Source != 1 || m.Target != 2 {
+		t.Errorf("move in time: got %v, want claim of (1, 2)", m)
+	}
+}
@@ -0,0 +1,67 @@
+package game
+
+import "time"
+
+// VoronoiPlayer claims the river next to its network that leaves it the
+// best Territory margin over the strongest of the others.
+type VoronoiPlayer struct {
+	BaselinePlayer
+	Candidates int `json:"candidates"` // rivers evaluated per move
+	deadline   deadline
+}
+
+func (p *VoronoiPlayer) Name() string { return "voronoi" }
+
+func (p *VoronoiPlayer) Params() []Param {
+	return []Param{
+		{Name: "candidates", Default: 30, Doc: "rivers evaluated per move, the most valuable ones first", Min: 1, Max: 200, Integer: true},
+	}
+}
+
+func (p *VoronoiPlayer) SetParam(name string, value float64) {
+	switch name {
+	case "candidates":
+		p.Candidates = int(value)
+	}
+}
+
+func (p *VoronoiPlayer) SetTimeBudget(budget time.Duration) {
+	p.deadline = deadline(time.Now().Add(budget))
+}
+
+// Returns the free rivers next to the punter's network, the ones with
+// the best immediate gain first.
+func (p *VoronoiPlayer) candidates() []*Edge {
+	near := p.FreeRiversNearby()
+	rankRivers(&p.BaselinePlayer, new(gainEvaluator), near)
+	if len(near) > p.Candidates {
+		near = near[:p.Candidates]
+	}
+	return near
+}
+
+func (p *VoronoiPlayer) MakeMove(moves []Move) Move {
+	p.PrepareForMove(moves)
+
+	var best *Edge
+	var bestMargin int64
+	for _, e := range p.candidates() {
+		// Out of time, choose among the rivers evaluated so far.
+		if best != nil && p.deadline.passed() {
+			break
+		}
+		e.Owner, p.AllEdges[e.Id^1].Owner = p.Punter, p.Punter
+		t := MakeTerritory(&p.Graph, p.Punters)
+		e.Owner, p.AllEdges[e.Id^1].Owner = -1, -1
+
+		margin := t.Margin(p.Punter) + p.FutureProgress(e.Src, e.Dst)
+		if best == nil || margin > bestMargin {
+			best, bestMargin = e, margin
+		}
+	}
+
+	if best == nil {
+		return p.MakePassMove()
+	}
+	return p.MakeClaimMove(best.Src, best.Dst)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"common"
+	"flag"
+	"fmt"
+	"game"
+	"log"
+	"os"
+	"sort"
+)
+
+var flagDiameter = flag.Bool("diameter", true, "Compute the diameter (BFS from every site)")
+
+// Returns the component id of every vertex and the sizes of the components.
+func components(g *game.Graph) (comp []int, sizes []int) {
+	comp = make([]int, g.NumSites)
+	for i := range comp {
+		comp[i] = -1
+	}
+	for s := 0; s < g.NumSites; s++ {
+		if comp[s] >= 0 {
+			continue
+		}
+		c := len(sizes)
+		sizes = append(sizes, 0)
+		d := g.SSSP(s)
+		for v, dv := range d {
+			if dv >= 0 {
+				comp[v] = c
+				sizes[c]++
+			}
+		}
+	}
+	return
+}
+
+// Returns the largest finite distance between two sites.
+func diameter(g *game.Graph) (diam int) {
+	for s := 0; s < g.NumSites; s++ {
+		for _, d := range g.SSSP(s) {
+			if d > diam {
+				diam = d
+			}
+		}
+	}
+	return
+}
+
+func report(path string) bool {
+	m, err := common.ReadMap(path)
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+
+	fmt.Println("Map:", path)
+
+	problems := m.Validate()
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Println("  Error:", p)
+		}
+		return false
+	}
+
+	var index common.CompressedIndex
+	sites := make([]int, len(m.Sites))
+	for i, site := range m.Sites {
+		sites[i] = site.Id
+	}
+	index.Setup(sites)
+
+	var g game.Graph
+	g.InitGraph(common.MakeGameMap(&m, &index))
+
+	fmt.Println("  Sites:", g.NumSites)
+	fmt.Println("  Rivers:", len(m.Rivers))
+	fmt.Println("  Mines:", len(g.Mines))
+
+	comp, sizes := components(&g)
+	if len(sizes) > 1 {
+		fmt.Printf("  Warning: map is disconnected, %v components of sizes %v\n", len(sizes), sizes)
+		for i, mine := range g.Mines {
+			fmt.Printf("    mine %v: component %v\n", m.Mines[i], comp[mine])
+		}
+	} else {
+		fmt.Println("  Connected: yes")
+	}
+
+	if *flagDiameter {
+		fmt.Println("  Diameter:", diameter(&g))
+	}
+
+	degrees := make(map[int]int)
+	for _, es := range g.Edges {
+		degrees[len(es)]++
+	}
+	keys := make([]int, 0, len(degrees))
+	for d := range degrees {
+		keys = append(keys, d)
+	}
+	sort.Ints(keys)
+	fmt.Println("  Degree distribution:")
+	for _, d := range keys {
+		fmt.Printf("    %3d: %v\n", d, degrees[d])
+	}
+
+	fmt.Println("  Score upper bound (no futures):", g.ScoreUpperBound())
+	fmt.Println("  Future upper bound:", g.FutureUpperBound())
+	return true
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] map.json...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ok := true
+	for i, path := range flag.Args() {
+		if i > 0 {
+			fmt.Println()
+		}
+		if !report(path) {
+			ok = false
+		}
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
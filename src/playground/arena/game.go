@@ -0,0 +1,423 @@
+// Package arena runs games of bots against each other, for the
+// playground and for tests.
+package arena
+
+import (
+	"common"
+	"errors"
+	"fmt"
+	"game"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Rules decide when the game ends and who becomes a zombie.
+type Rules int
+
+const (
+	// The game lasts exactly as many moves as there are rivers, passes
+	// and splurges included. A punter becomes a zombie after MaxTimeouts
+	// timeouts and passes for the rest of the game.
+	OfficialRules Rules = iota
+	// The game lasts until all rivers are claimed or all punters are
+	// zombies. A punter becomes a zombie after MaxPasses passes in a row
+	// and loses its turns.
+	LegacyRules
+)
+
+func (r Rules) String() string {
+	if r == LegacyRules {
+		return "legacy"
+	}
+	return "official"
+}
+
+func ParseRules(s string) (Rules, error) {
+	switch s {
+	case "official":
+		return OfficialRules, nil
+	case "legacy":
+		return LegacyRules, nil
+	}
+	return OfficialRules, fmt.Errorf("unknown rules: %q", s)
+}
+
+// Parses a comma-separated list of settings, e.g. futures,splurges.
+func ParseSettings(s string) (settings game.Settings, err error) {
+	if s == "" {
+		return
+	}
+	for _, part := range strings.Split(s, ",") {
+		switch part {
+		case "futures":
+			settings.FuturesMode = true
+		case "splurges":
+			settings.SplurgesMode = true
+		case "options":
+			settings.OptionsMode = true
+		default:
+			return settings, fmt.Errorf("bad value of settings: %q, can't read %q", s, part)
+		}
+	}
+	return
+}
+
+// Reads the map and checks that it can be played on.
+func LoadMap(path string) (m common.Map, err error) {
+	if m, err = common.ReadMap(path); err != nil {
+		return
+	}
+	if problems := m.Validate(); len(problems) > 0 {
+		return m, fmt.Errorf("bad map %v: %v", path, strings.Join(problems, "; "))
+	}
+	return
+}
+
+// Loads the maps of a comma-separated list of paths or patterns such as
+// maps/*.json.
+func LoadMaps(s string) (maps []*common.Map, err error) {
+	for _, pattern := range strings.Split(s, ",") {
+		paths, err := filepath.Glob(pattern)
+		if err != nil || len(paths) == 0 {
+			return nil, fmt.Errorf("no maps match %v", pattern)
+		}
+		for _, path := range paths {
+			m, err := LoadMap(path)
+			if err != nil {
+				return nil, err
+			}
+			maps = append(maps, &m)
+		}
+	}
+	return
+}
+
+const (
+	MaxPasses   = 10 // passes in a row that make a zombie in the legacy rules
+	MaxTimeouts = 10 // timeouts that make a zombie in the official rules
+)
+
+// Hooks are called on the events of the game. Any of them may be nil.
+type Hooks struct {
+	Move        func(punter int, move common.Move)
+	InvalidMove func(punter int, move common.Move, err error)
+	End         func(r *Result)
+}
+
+// Result is the outcome of the game.
+type Result struct {
+	Names        []string         // the bots, with the choice of a meta bot
+	Bots         []common.BotSpec // the bots as they were made, e.g. learned:@weights.json
+	Scores       []int64
+	FutureScores [][]int64 // FutureScores[p][i] is the score of future i of punter p
+}
+
+// Game is a game of several players on a map. The moves are made one by
+// one with Step.
+type Game struct {
+	Settings game.Settings
+	Rules    Rules
+	Timeout  time.Duration // the time limit of a move of a bot, none if zero
+	Hooks    Hooks
+
+	graph   Graph
+	punters []common.PlayerProxy
+	futures [][]game.Future // in the compressed format
+
+	moves      []common.Move // the last move of every punter
+	played     []common.Move // all moves of the game as the others saw them
+	history    []common.Move // moves before the game's start, see NewGameFromPosition
+	seen       []bool        // seen[p] if punter p was told the history
+	passes     []int         // passes in a row of every punter
+	timeouts   []int
+	zombies    []bool
+	numZombies int
+	claimed    int // number of claimed rivers
+	numMoves   int
+	next       int // the punter to move
+	turn       int
+}
+
+// Creates the game and sets up the players.
+func NewGame(m *common.Map, settings game.Settings, punters []common.PlayerProxy) (*Game, error) {
+	n := len(punters)
+	if n == 0 {
+		return nil, errors.New("no players")
+	}
+
+	g := &Game{
+		Settings: settings,
+		graph:    MakeGraph(m),
+		punters:  punters,
+		futures:  make([][]game.Future, n),
+		moves:    make([]common.Move, n),
+		passes:   make([]int, n),
+		timeouts: make([]int, n),
+		seen:     make([]bool, n),
+		zombies:  make([]bool, n),
+	}
+	for i := range punters {
+		punters[i].Setup(i, n, m, settings)
+		if settings.FuturesMode {
+			fs, err := g.graph.compressFutures(punters[i].GetFutures())
+			if err != nil {
+				return nil, fmt.Errorf("punter %v %v: %v", i, punters[i].Name(), err)
+			}
+			g.futures[i] = fs
+		}
+		g.moves[i].Pass = &common.PassMove{Punter: i}
+	}
+	return g, nil
+}
+
+// Creates the game of the given bots.
+func NewGameOfBots(m *common.Map, settings game.Settings, bots []common.BotSpec) (*Game, error) {
+	punters := make([]common.PlayerProxy, len(bots))
+	for i, bot := range bots {
+		var err error
+		if punters[i], err = common.MakePlayerProxyWithParams(bot.Name, bot.Params); err != nil {
+			return nil, err
+		}
+	}
+	return NewGame(m, settings, punters)
+}
+
+func (g *Game) NumPunters() int { return len(g.punters) }
+
+// Returns the player of the punter.
+func (g *Game) Punter(punter int) *common.PlayerProxy { return &g.punters[punter] }
+
+// Returns the current state of the map.
+func (g *Game) Graph() *Graph { return &g.graph }
+
+// Returns the number of full rounds played.
+func (g *Game) Turn() int { return g.turn }
+
+func (g *Game) Zombie(punter int) bool { return g.zombies[punter] }
+
+// Returns the number of moves made, passes and zombies' moves included.
+func (g *Game) NumMoves() int { return g.numMoves }
+
+func (g *Game) Over() bool {
+	numRivers := len(g.graph.AllEdges) / 2
+	if g.Rules == LegacyRules {
+		return g.claimed == numRivers || g.numZombies == len(g.punters)
+	}
+	return g.numMoves == numRivers
+}
+
+func (g *Game) makeZombie(punter int) {
+	g.zombies[punter] = true
+	g.numZombies++
+}
+
+// Checks the move and applies it to the map.
+func (g *Game) apply(punter int, move *common.Move) error {
+	switch {
+	case move.Pass != nil:
+		return nil
+
+	case move.Claim != nil:
+		if err := g.graph.ClaimEdge(punter, move.Claim.Source, move.Claim.Target); err != nil {
+			return err
+		}
+		g.claimed++
+		return nil
+
+	case move.Splurge != nil:
+		// A splurge of n rivers is paid with n-1 passes.
+		route := move.Splurge.Route
+		rivers := len(route) - 1
+		if !g.Settings.SplurgesMode {
+			return errors.New("splurges are off")
+		}
+		if rivers < 1 {
+			return errors.New("splurge without rivers")
+		}
+		if g.passes[punter]+1 < rivers {
+			return fmt.Errorf("%v rivers need %v passes, the punter has %v", rivers, rivers-1, g.passes[punter])
+		}
+		edges := make([]*game.Edge, rivers)
+		for i := range edges {
+			e, err := g.graph.freeEdge(route[i], route[i+1])
+			if err != nil {
+				return err
+			}
+			for _, prev := range edges[:i] {
+				if prev.Id/2 == e.Id/2 {
+					return fmt.Errorf("river (%v, %v) is claimed twice", route[i], route[i+1])
+				}
+			}
+			edges[i] = e
+		}
+		for _, e := range edges {
+			g.graph.SetEdgeOwnership(e.Src, e.Dst, punter)
+		}
+		g.claimed += rivers
+		return nil
+
+	case move.Option != nil:
+		return errors.New("options are not supported")
+	}
+	return errors.New("empty move")
+}
+
+// Makes the move of the next punter, returns false if the game is over.
+// Invalid and late moves are taken as passes.
+func (g *Game) Step() bool {
+	if g.Over() {
+		return false
+	}
+	if g.Rules == LegacyRules {
+		for g.zombies[g.next] {
+			g.advance()
+		}
+	}
+
+	punter := g.next
+	move := common.Move{Pass: &common.PassMove{Punter: punter}}
+	if !g.zombies[punter] {
+		move = g.makeMove(punter)
+	}
+	g.record(punter, move)
+	return true
+}
+
+// Makes the move for the next punter instead of its player, which will
+// see the move as its own. An invalid move is an error and is not made.
+func (g *Game) Play(move common.Move) error {
+	if g.Over() {
+		return errors.New("the game is over")
+	}
+	punter := g.next
+	if p, ok := movePunter(&move); !ok || p != punter {
+		return fmt.Errorf("not a move of punter %v: %v", punter, move.String())
+	}
+	if err := g.apply(punter, &move); err != nil {
+		return err
+	}
+	g.record(punter, move)
+	return nil
+}
+
+func movePunter(m *common.Move) (int, bool) {
+	switch {
+	case m.Claim != nil:
+		return m.Claim.Punter, true
+	case m.Pass != nil:
+		return m.Pass.Punter, true
+	case m.Splurge != nil:
+		return m.Splurge.Punter, true
+	case m.Option != nil:
+		return m.Option.Punter, true
+	}
+	return 0, false
+}
+
+// Updates the state after the move of the punter.
+func (g *Game) record(punter int, move common.Move) {
+	if move.Pass != nil {
+		g.passes[punter]++
+	} else {
+		g.passes[punter] = 0
+	}
+	if g.Rules == LegacyRules && g.passes[punter] == MaxPasses {
+		g.makeZombie(punter)
+	}
+	g.moves[punter] = move
+	g.played = append(g.played, move)
+	g.numMoves++
+	g.advance()
+
+	if g.Over() && g.Hooks.End != nil {
+		r := g.Result()
+		g.Hooks.End(&r)
+	}
+}
+
+// Asks the punter for a move and applies it, returns the move as the
+// others see it.
+func (g *Game) makeMove(punter int) common.Move {
+	moves := g.moves
+	if !g.seen[punter] {
+		moves = append(append([]common.Move(nil), g.history...), g.moves...)
+		g.seen[punter] = true
+	}
+
+	// The humans take their time, the bots are told the limit.
+	limit := g.Timeout
+	if _, human := g.punters[punter].Player.(*game.HumanPlayer); human {
+		limit = 0
+	}
+	if limit > 0 {
+		g.punters[punter].SetTimeBudget(limit)
+	}
+
+	start := time.Now()
+	move := g.punters[punter].MakeMove(moves)
+	elapsed := time.Since(start)
+	if g.Hooks.Move != nil {
+		g.Hooks.Move(punter, move)
+	}
+
+	var err error
+	if limit > 0 && elapsed > limit {
+		err = fmt.Errorf("the move took %v, the limit is %v", elapsed, limit)
+		g.timeouts[punter]++
+		if g.Rules == OfficialRules && g.timeouts[punter] == MaxTimeouts {
+			g.makeZombie(punter)
+		}
+	} else {
+		err = g.apply(punter, &move)
+	}
+	if err != nil {
+		if g.Hooks.InvalidMove != nil {
+			g.Hooks.InvalidMove(punter, move, err)
+		}
+		return common.Move{Pass: &common.PassMove{Punter: punter}}
+	}
+	return move
+}
+
+func (g *Game) advance() {
+	g.next++
+	if g.next == len(g.punters) {
+		g.next = 0
+		g.turn++
+	}
+}
+
+// Plays the game to the end and returns the result.
+func (g *Game) Run() Result {
+	for g.Step() {
+	}
+	return g.Result()
+}
+
+// Returns the scores of the punters for the current state of the map.
+func (g *Game) Result() (r Result) {
+	n := len(g.punters)
+	r.Names = make([]string, n)
+	r.Bots = make([]common.BotSpec, n)
+	r.Scores = make([]int64, n)
+	r.FutureScores = make([][]int64, n)
+	for p := range g.punters {
+		pp := &g.punters[p]
+		r.Names[p] = pp.Spec().String()
+		if m, ok := pp.Player.(*game.MetaPlayer); ok && m.Bot != nil {
+			r.Names[p] += fmt.Sprintf(" (%v)", common.BotSpec{Name: m.Choice, Params: m.Params})
+		}
+		r.Bots[p] = common.BotSpec{Name: pp.Bot, Params: pp.Params}
+		if pp.Bot == "" {
+			r.Bots[p].Name = pp.Name()
+		}
+		r.Scores[p] = g.graph.Score(p, g.futures[p])
+
+		scorer := game.MakeScorer(&g.graph.Graph, p, nil)
+		for _, f := range g.futures[p] {
+			r.FutureScores[p] = append(r.FutureScores[p], scorer.FutureScore(f))
+		}
+	}
+	return
+}
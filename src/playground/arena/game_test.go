@@ -0,0 +1,235 @@
+package arena
+
+import (
+	"common"
+	"game"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var splurgeScripts = []common.BotSpec{
+	{Name: "scripted:pass; pass; splurge 10 11 12 13"},
+	{Name: "scripted:14 15; 15 16"},
+}
+
+func TestScriptedGame(t *testing.T) {
+	m := loadMap(t, "testdata/disconnected.json")
+	g, err := NewGameOfBots(&m, game.Settings{SplurgesMode: true}, splurgeScripts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Rules = LegacyRules
+
+	var moves []common.Move
+	var ended *Result
+	g.Hooks.Move = func(punter int, move common.Move) { moves = append(moves, move) }
+	g.Hooks.End = func(r *Result) { ended = r }
+
+	// Both punters pass, then punter 1 claims.
+	for i := 0; i < 3; i++ {
+		g.Step()
+	}
+	if o := owner(g.Graph(), 14, 15); o != 1 || g.Turn() != 1 {
+		t.Errorf("after 3 steps: owner of (14, 15) %v, turn %v", o, g.Turn())
+	}
+
+	r := g.Run()
+
+	// The splurge is paid with two passes and claims three rivers: 1 + 4 + 9
+	// for mine 10. Both mines 14 and 16 reach their two neighbours.
+	if !reflect.DeepEqual(r.Scores, []int64{14, 4}) {
+		t.Errorf("scores: got %v, want [14 4]", r.Scores)
+	}
+	if r.Names[0] != "scripted" {
+		t.Errorf("name: got %q, want scripted", r.Names[0])
+	}
+	if ended == nil || !reflect.DeepEqual(ended.Scores, r.Scores) {
+		t.Errorf("end hook: got %v, want %v", ended, r)
+	}
+	if moves[4].Splurge == nil {
+		t.Errorf("fifth move: got %v, want splurge", moves[4].String())
+	}
+
+	// Both become zombies after MaxPasses passes in a row.
+	if !g.Zombie(0) || !g.Zombie(1) || len(moves) != 5+2*MaxPasses {
+		t.Errorf("zombies: %v %v after %v moves", g.Zombie(0), g.Zombie(1), len(moves))
+	}
+}
+
+func TestOfficialRules(t *testing.T) {
+	m := loadMap(t, "testdata/disconnected.json")
+	g, err := NewGameOfBots(&m, game.Settings{SplurgesMode: true}, splurgeScripts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The game ends after 6 moves, one per river, with river (14, 16)
+	// still free.
+	r := g.Run()
+	if g.NumMoves() != 6 || g.Turn() != 3 || owner(g.Graph(), 14, 16) != -1 {
+		t.Errorf("game over after %v moves and %v turns", g.NumMoves(), g.Turn())
+	}
+	if !reflect.DeepEqual(r.Scores, []int64{14, 4}) {
+		t.Errorf("scores: got %v, want [14 4]", r.Scores)
+	}
+}
+
+// A map with a path of n rivers from mine 0.
+func pathMap(n int) (m common.Map) {
+	m.Sites = append(m.Sites, common.Site{Id: 0})
+	for i := 1; i <= n; i++ {
+		m.Sites = append(m.Sites, common.Site{Id: i})
+		m.Rivers = append(m.Rivers, game.River{Source: i - 1, Target: i})
+	}
+	m.Mines = []int{0}
+	return
+}
+
+func TestTimeouts(t *testing.T) {
+	m := pathMap(30)
+	for _, rules := range []Rules{OfficialRules, LegacyRules} {
+		g, err := NewGameOfBots(&m, game.Settings{}, []common.BotSpec{{Name: "baseline"}, {Name: "baseline"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.Rules = rules
+		g.Timeout = time.Nanosecond // every move is late
+
+		calls := 0
+		g.Hooks.Move = func(punter int, move common.Move) { calls++ }
+		g.Run()
+
+		// In the official rules the zombies keep passing until the end of the
+		// game, in the legacy rules they are zombies from the passes.
+		if !g.Zombie(0) || !g.Zombie(1) {
+			t.Errorf("%v rules: zombies %v %v", rules, g.Zombie(0), g.Zombie(1))
+		}
+		wantMoves := 30
+		if rules == LegacyRules {
+			wantMoves = 2 * MaxPasses
+		}
+		if calls != 2*MaxTimeouts || g.NumMoves() != wantMoves {
+			t.Errorf("%v rules: %v moves asked, %v made, want %v and %v", rules, calls, g.NumMoves(), 2*MaxTimeouts, wantMoves)
+		}
+	}
+}
+
+// A player that remembers its time budgets.
+type budgetPlayer struct {
+	game.ZombiePlayer
+	budgets []time.Duration
+}
+
+func (p *budgetPlayer) SetTimeBudget(budget time.Duration) {
+	p.budgets = append(p.budgets, budget)
+}
+
+func TestTimeBudget(t *testing.T) {
+	m := pathMap(4)
+	players := []*budgetPlayer{{}, {}}
+	punters := make([]common.PlayerProxy, len(players))
+	for i, p := range players {
+		punters[i].Player = p
+	}
+	g, err := NewGame(&m, game.Settings{}, punters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Timeout = time.Second
+	g.Run()
+	for i, p := range players {
+		if len(p.budgets) != 2 || p.budgets[0] != time.Second {
+			t.Errorf("punter %v: budgets %v, want 1s before each of 2 moves", i, p.budgets)
+		}
+	}
+}
+
+func TestParseSettings(t *testing.T) {
+	s, err := ParseSettings("futures,splurges,options")
+	if err != nil || s != (game.Settings{FuturesMode: true, SplurgesMode: true, OptionsMode: true}) {
+		t.Errorf("settings: got %v, %v", s, err)
+	}
+	if s, err := ParseSettings(""); err != nil || s != (game.Settings{}) {
+		t.Errorf("no settings: got %v, %v", s, err)
+	}
+	if _, err := ParseSettings("futures,nonsense"); err == nil {
+		t.Error("bad settings: no error")
+	}
+}
+
+func owner(g *Graph, from, to int) int {
+	u, v := g.Index.Forward[from], g.Index.Forward[to]
+	for _, e := range g.AllEdges {
+		if e.Src == u && e.Dst == v {
+			return e.Owner
+		}
+	}
+	return -1
+}
+
+// A player that insists on the same move.
+type stubbornPlayer struct {
+	game.ZombiePlayer
+	move game.Move
+}
+
+func (p *stubbornPlayer) MakeMove(moves []game.Move) game.Move {
+	m := p.move
+	m.Punter = p.Punter
+	return m
+}
+
+func TestInvalidMoves(t *testing.T) {
+	m := loadMap(t, "testdata/disconnected.json")
+	punters := make([]common.PlayerProxy, 3)
+	for i, move := range []game.Move{
+		game.MakeClaimMove(0, 0, 1),
+		game.MakeClaimMove(0, 1, 0),
+		game.MakeSplurgeMove(0, []int{1, 2, 3}),
+	} {
+		punters[i].Player = &stubbornPlayer{move: move}
+	}
+	g, err := NewGame(&m, game.Settings{SplurgesMode: true}, punters)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var invalid []string
+	g.Hooks.InvalidMove = func(punter int, move common.Move, err error) {
+		invalid = append(invalid, err.Error())
+	}
+	for i := 0; i < 3; i++ {
+		g.Step()
+	}
+
+	want := []string{
+		"river (11, 10) is claimed by punter 0",
+		"2 rivers need 1 passes, the punter has 0",
+	}
+	if !reflect.DeepEqual(invalid, want) {
+		t.Errorf("invalid moves: got %q, want %q", invalid, want)
+	}
+
+	// The others see invalid moves as passes.
+	var seen []game.Move
+	g.punters[0].Player = &recordingPlayer{moves: &seen}
+	g.Step()
+	if len(seen) != 3 || seen[0].Type != game.Claim || seen[1].Type != game.Pass || seen[2].Type != game.Pass {
+		t.Errorf("moves seen by punter 0: got %v", seen)
+	}
+}
+
+type recordingPlayer struct {
+	game.ZombiePlayer
+	moves *[]game.Move
+}
+
+func (p *recordingPlayer) MakeMove(moves []game.Move) game.Move {
+	*p.moves = moves
+	return game.MakePassMove(p.Punter)
+}
+
+func claim(punter, source, target int) common.Move {
+	return common.Move{Claim: &common.ClaimMove{Punter: punter, Source: source, Target: target}}
+}
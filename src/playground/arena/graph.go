@@ -0,0 +1,78 @@
+package arena
+
+import (
+	"common"
+	"fmt"
+	"game"
+)
+
+// Graph is the arena's view of the map: the graph in the compressed
+// format and the index to convert site ids from the map.
+type Graph struct {
+	game.Graph
+	Index common.CompressedIndex
+}
+
+func MakeGraph(m *common.Map) (g Graph) {
+	sites := make([]int, len(m.Sites))
+	for i, site := range m.Sites {
+		sites[i] = site.Id
+	}
+	g.Index.Setup(sites)
+	g.InitGraph(common.MakeGameMap(m, &g.Index))
+	return g
+}
+
+// Returns the free river between sites from and to of the map.
+func (g *Graph) freeEdge(from, to int) (*game.Edge, error) {
+	u, okU := g.Index.Forward[from]
+	v, okV := g.Index.Forward[to]
+	if okU && okV {
+		for _, eId := range g.Edges[u] {
+			e := &g.AllEdges[eId]
+			if e.Dst != v {
+				continue
+			}
+			if e.Owner >= 0 {
+				return nil, fmt.Errorf("river (%v, %v) is claimed by punter %v", from, to, e.Owner)
+			}
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("no river (%v, %v)", from, to)
+}
+
+// Gives the river between sites from and to of the map to the owner.
+func (g *Graph) ClaimEdge(owner, from, to int) error {
+	e, err := g.freeEdge(from, to)
+	if err != nil {
+		return err
+	}
+	g.SetEdgeOwnership(e.Src, e.Dst, owner)
+	return nil
+}
+
+// Converts the futures to the compressed format. A future must start
+// at a mine and end at a site.
+func (g *Graph) compressFutures(futures []game.Future) ([]game.Future, error) {
+	fs := make([]game.Future, len(futures))
+	for i, f := range futures {
+		u, okU := g.Index.Forward[f.Src]
+		v, okV := g.Index.Forward[f.Dst]
+		if !okU || g.MineIndex(u) < 0 {
+			return nil, fmt.Errorf("future (%v, %v) doesn't start at a mine", f.Src, f.Dst)
+		}
+		if !okV {
+			return nil, fmt.Errorf("future (%v, %v) doesn't end at a site", f.Src, f.Dst)
+		}
+		fs[i] = game.Future{Src: u, Dst: v}
+	}
+	return fs, nil
+}
+
+// Returns the score of the punter with the futures in the compressed
+// format.
+func (g *Graph) Score(punter int, futures []game.Future) int64 {
+	scorer := game.MakeScorer(&g.Graph, punter, futures)
+	return scorer.Score()
+}
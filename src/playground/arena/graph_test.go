@@ -0,0 +1,58 @@
+package arena
+
+import (
+	"common"
+	"game"
+	"testing"
+)
+
+func loadMap(t *testing.T, path string) common.Map {
+	m, err := common.ReadMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDisconnectedMap(t *testing.T) {
+	m := loadMap(t, "testdata/disconnected.json")
+	g := MakeGraph(&m)
+
+	if s := g.ScoreUpperBound(); s != 18 {
+		t.Errorf("score upper bound: got %v, want 18", s)
+	}
+	if s := g.FutureUpperBound(); s != 27 {
+		t.Errorf("future upper bound: got %v, want 27", s)
+	}
+
+	for _, r := range [][3]int{{0, 10, 11}, {0, 11, 12}, {1, 14, 15}} {
+		if err := g.ClaimEdge(r[0], r[1], r[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.ClaimEdge(1, 11, 10); err == nil {
+		t.Error("claimed river (11, 10) twice")
+	}
+	if err := g.ClaimEdge(1, 10, 12); err == nil {
+		t.Error("claimed unknown river (10, 12)")
+	}
+
+	// Sites 11 and 12 for mine 10 plus the future bonus; the future from
+	// mine 14 to the other component is ignored.
+	fs, err := g.compressFutures([]game.Future{{Src: 10, Dst: 12}, {Src: 14, Dst: 13}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := g.Score(0, fs); s != 5+8 {
+		t.Errorf("punter 0 score: got %v, want 13", s)
+	}
+	if fs, err = g.compressFutures([]game.Future{{Src: 17, Dst: 10}}); err != nil {
+		t.Fatal(err)
+	}
+	if s := g.Score(1, fs); s != 1 {
+		t.Errorf("punter 1 score: got %v, want 1", s)
+	}
+	if _, err := g.compressFutures([]game.Future{{Src: 11, Dst: 10}}); err == nil {
+		t.Error("future from a site that is not a mine: no error")
+	}
+}
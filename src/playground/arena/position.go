@@ -0,0 +1,253 @@
+package arena
+
+import (
+	"bufio"
+	"bytes"
+	"common"
+	"encoding/json"
+	"fmt"
+	"game"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Position is a mid-game position for testing the decisions of the bots,
+// like a line of an EPD chess suite. The sites are the map's ids.
+type Position struct {
+	Name     string        `json:"name"`
+	Map      string        `json:"map"` // path to the map, absolute or relative to the suite file
+	Settings game.Settings `json:"settings,omitempty"`
+	Punters  int           `json:"punters"`
+	Punter   int           `json:"punter"`            // the seat to move
+	Futures  []game.Future `json:"futures,omitempty"` // of the punter to move
+	Claimed  [][][2]int    `json:"claimed"`           // Claimed[p] are the rivers of punter p
+	Moves    int           `json:"moves,omitempty"`   // moves made, the number of claimed rivers if zero
+	Best     [][2]int      `json:"best,omitempty"`    // the best claims according to an expert
+
+	m *common.Map
+}
+
+func (pos *Position) GameMap() *common.Map { return pos.m }
+
+// Returns the claims of the position as moves.
+func (pos *Position) claims() (moves []common.Move) {
+	for p, rivers := range pos.Claimed {
+		for _, r := range rivers {
+			moves = append(moves, common.Move{Claim: &common.ClaimMove{Punter: p, Source: r[0], Target: r[1]}})
+		}
+	}
+	return
+}
+
+// Returns true if the move is one of the best claims.
+func (pos *Position) IsBest(move common.Move) bool {
+	if move.Claim == nil {
+		return false
+	}
+	for _, r := range pos.Best {
+		c := move.Claim
+		if (c.Source == r[0] && c.Target == r[1]) || (c.Source == r[1] && c.Target == r[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (pos *Position) validate() error {
+	if pos.Punters < 1 || pos.Punter < 0 || pos.Punter >= pos.Punters {
+		return fmt.Errorf("bad punter %v of %v", pos.Punter, pos.Punters)
+	}
+	if len(pos.Claimed) > pos.Punters {
+		return fmt.Errorf("rivers claimed by %v punters of %v", len(pos.Claimed), pos.Punters)
+	}
+	g := MakeGraph(pos.m)
+	for p, rivers := range pos.Claimed {
+		for _, r := range rivers {
+			if err := g.ClaimEdge(p, r[0], r[1]); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := g.compressFutures(pos.Futures); err != nil {
+		return err
+	}
+	for _, r := range pos.Best {
+		if _, err := g.freeEdge(r[0], r[1]); err != nil {
+			return fmt.Errorf("best move: %v", err)
+		}
+	}
+	return nil
+}
+
+// Reads the suite of positions, one JSON object per line. Empty lines
+// and lines starting with # are skipped. The maps are read once.
+func LoadSuite(path string) (suite []Position, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read suite: %v", err)
+	}
+	defer file.Close()
+
+	maps := make(map[string]*common.Map)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<24)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+
+		var pos Position
+		if err := json.Unmarshal(text, &pos); err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", path, line, err)
+		}
+		mapPath := pos.Map
+		if !filepath.IsAbs(mapPath) {
+			mapPath = filepath.Join(filepath.Dir(path), mapPath)
+		}
+		if pos.m = maps[mapPath]; pos.m == nil {
+			m, err := common.ReadMap(mapPath)
+			if err != nil {
+				return nil, fmt.Errorf("%v:%v: %v", path, line, err)
+			}
+			pos.m = &m
+			maps[mapPath] = pos.m
+		}
+		if err := pos.validate(); err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", path, line, err)
+		}
+		if pos.Name == "" {
+			pos.Name = fmt.Sprintf("%v:%v", filepath.Base(path), line)
+		}
+		suite = append(suite, pos)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read suite: %v", err)
+	}
+	return suite, nil
+}
+
+// Creates the game in the position, with the punter of the position to
+// move. The players see the claimed rivers in their first move. The
+// futures of the position replace the ones the punter chose.
+func NewGameFromPosition(pos *Position, punters []common.PlayerProxy) (*Game, error) {
+	if len(punters) != pos.Punters {
+		return nil, fmt.Errorf("%v players for %v punters", len(punters), pos.Punters)
+	}
+	g, err := NewGame(pos.m, pos.Settings, punters)
+	if err != nil {
+		return nil, err
+	}
+
+	g.history = pos.claims()
+	for _, m := range g.history {
+		if err := g.graph.ClaimEdge(m.Claim.Punter, m.Claim.Source, m.Claim.Target); err != nil {
+			return nil, err
+		}
+	}
+	g.claimed = len(g.history)
+	g.numMoves = pos.Moves
+	if g.numMoves == 0 {
+		g.numMoves = g.claimed
+	}
+	g.next = pos.Punter
+
+	if pos.Settings.FuturesMode {
+		if g.futures[pos.Punter], err = g.graph.compressFutures(pos.Futures); err != nil {
+			return nil, err
+		}
+		if fa, ok := punters[pos.Punter].Player.(game.FuturesAware); ok {
+			p := &punters[pos.Punter]
+			fs := make([]game.Future, len(pos.Futures))
+			for i, f := range pos.Futures {
+				fs[i] = game.Future{Src: p.Index.Forward[f.Src], Dst: p.Index.Forward[f.Dst]}
+			}
+			fa.SetFutures(fs)
+		}
+	}
+	return g, nil
+}
+
+func makePunters(n int, bot common.BotSpec) ([]common.PlayerProxy, error) {
+	punters := make([]common.PlayerProxy, n)
+	for i := range punters {
+		var err error
+		if punters[i], err = common.MakePlayerProxyWithParams(bot.Name, bot.Params); err != nil {
+			return nil, err
+		}
+	}
+	return punters, nil
+}
+
+// Asks the bot for its move in the position. The other punters are
+// zombies.
+func (pos *Position) Ask(bot common.BotSpec) (move common.Move, elapsed time.Duration, err error) {
+	punters, err := makePunters(pos.Punters, common.BotSpec{Name: "zombie"})
+	if err != nil {
+		return
+	}
+	if punters[pos.Punter], err = common.MakePlayerProxyWithParams(bot.Name, bot.Params); err != nil {
+		return
+	}
+	g, err := NewGameFromPosition(pos, punters)
+	if err != nil {
+		return
+	}
+	g.Hooks.Move = func(punter int, m common.Move) { move = m }
+	start := time.Now()
+	g.Step()
+	return move, time.Since(start), nil
+}
+
+// Plays the move in the position and the rest of the game with the bot
+// for every punter, returns the final score of the punter to move.
+func (pos *Position) Playout(move common.Move, bot common.BotSpec) (int64, error) {
+	punters, err := makePunters(pos.Punters, bot)
+	if err != nil {
+		return 0, err
+	}
+	g, err := NewGameFromPosition(pos, punters)
+	if err != nil {
+		return 0, err
+	}
+	if err := g.Play(move); err != nil {
+		return 0, err
+	}
+	return g.Run().Scores[pos.Punter], nil
+}
+
+// Returns the free rivers next to the sites the punter to move reaches
+// from the mines, or all free rivers if it reaches none.
+func (pos *Position) Candidates() (rivers [][2]int) {
+	g := MakeGraph(pos.m)
+	for _, m := range pos.claims() {
+		g.ClaimEdge(m.Claim.Punter, m.Claim.Source, m.Claim.Target)
+	}
+
+	scorer := game.MakeScorer(&g.Graph, pos.Punter, nil)
+	near := make([]bool, g.NumSites)
+	for _, mine := range g.Mines {
+		for v := range near {
+			if scorer.Connected(mine, v) {
+				near[v] = true
+			}
+		}
+	}
+
+	var all [][2]int
+	for _, e := range g.AllEdges {
+		if e.Owner >= 0 || e.Src > e.Dst {
+			continue
+		}
+		r := [2]int{g.Index.Backward[e.Src], g.Index.Backward[e.Dst]}
+		all = append(all, r)
+		if near[e.Src] || near[e.Dst] {
+			rivers = append(rivers, r)
+		}
+	}
+	if len(rivers) == 0 {
+		return all
+	}
+	return rivers
+}
@@ -0,0 +1,91 @@
+package arena
+
+import (
+	"common"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadSuite(t *testing.T) {
+	suite, err := LoadSuite("testdata/suite.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suite) != 2 || suite[0].Name != "extend" || suite[1].Name != "suite.jsonl:3" {
+		t.Fatalf("got %v positions: %+v", len(suite), suite)
+	}
+	if suite[0].GameMap() != suite[1].GameMap() {
+		t.Error("the map is read twice")
+	}
+
+	dir, err := ioutil.TempDir("", "suite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mapPath, _ := filepath.Abs("testdata/disconnected.json")
+	for _, c := range []struct{ pos, err string }{
+		{`{"punters": 2, "punter": 2}`, "bad punter 2 of 2"},
+		{`{"punters": 2, "claimed": [[[10, 11]], [[11, 10]]]}`, "river (11, 10) is claimed by punter 0"},
+		{`{"punters": 2, "futures": [{"source": 11, "target": 12}]}`, "future (11, 12) doesn't start at a mine"},
+		{`{"punters": 2, "claimed": [[[10, 11]]], "best": [[10, 11]]}`, "best move: river (10, 11) is claimed by punter 0"},
+	} {
+		path := filepath.Join(dir, "suite.jsonl")
+		pos := strings.Replace(c.pos, "{", `{"map": "`+mapPath+`", `, 1)
+		if err := ioutil.WriteFile(path, []byte(pos), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSuite(path); err == nil || !strings.HasSuffix(err.Error(), c.err) {
+			t.Errorf("%v: got error %v, want %q", c.pos, err, c.err)
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	suite, err := LoadSuite("testdata/suite.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseline := common.BotSpec{Name: "baseline"}
+	pos := &suite[0]
+	move, _, err := pos.Ask(baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pos.IsBest(move) {
+		t.Errorf("baseline: got %v, want claim of (11, 12)", move.String())
+	}
+
+	// Punter 0 extends its path to 13, punter 1 takes (15, 16) and the
+	// rest of the game doesn't matter.
+	score, err := pos.Playout(claim(0, 11, 12), baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != 14 {
+		t.Errorf("playout: got %v, want 14", score)
+	}
+	if _, err := pos.Playout(claim(0, 10, 11), baseline); err == nil {
+		t.Error("playout of a claimed river: no error")
+	}
+
+	// Next to the mines of punter 1 too.
+	want := [][2]int{{11, 12}, {15, 16}, {14, 16}}
+	if c := pos.Candidates(); !reflect.DeepEqual(c, want) {
+		t.Errorf("candidates: got %v, want %v", c, want)
+	}
+
+	// The future of punter 1 replaces the one it would choose.
+	pos = &suite[1]
+	if score, err = pos.Playout(claim(1, 14, 16), common.BotSpec{Name: "zombie"}); err != nil {
+		t.Fatal(err)
+	}
+	if score != 2+2+1 {
+		t.Errorf("playout with the future: got %v, want 5", score)
+	}
+}
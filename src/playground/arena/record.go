@@ -0,0 +1,86 @@
+package arena
+
+import (
+	"bufio"
+	"common"
+	"encoding/json"
+	"fmt"
+	"game"
+	"os"
+	"strings"
+)
+
+// Record is a finished game: the bots, their futures, the moves and the
+// scores, with the site ids from the map. The records are stored one per
+// line.
+type Record struct {
+	Map      string           `json:"map"` // the path to the map
+	Settings game.Settings    `json:"settings"`
+	Bots     []common.BotSpec `json:"bots"`
+	Futures  [][]game.Future  `json:"futures,omitempty"`
+	Moves    []common.Move    `json:"moves"`
+	Scores   []int64          `json:"scores"`
+}
+
+// Returns the record of the game so far, without the map. The claims
+// before the start of a game from a position are not in it.
+func (g *Game) Record() (r Record) {
+	res := g.Result()
+	r.Settings = g.Settings
+	r.Bots = res.Bots
+	r.Moves = append([]common.Move(nil), g.played...)
+	r.Scores = res.Scores
+	if g.Settings.FuturesMode {
+		r.Futures = make([][]game.Future, len(g.punters))
+		for p, fs := range g.futures {
+			for _, f := range fs {
+				r.Futures[p] = append(r.Futures[p], game.Future{Src: g.graph.Index.Backward[f.Src], Dst: g.graph.Index.Backward[f.Dst]})
+			}
+		}
+	}
+	return
+}
+
+// Appends the record to the file.
+func AppendRecord(path string, r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintln(f, string(data)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Reads the records from the file, skipping empty lines.
+func ReadRecords(path string) (records []Record, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<28)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal([]byte(text), &r); err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", path, line, err)
+		}
+		if len(r.Scores) == 0 || len(r.Bots) != len(r.Scores) {
+			return nil, fmt.Errorf("%v:%v: no scores of the bots", path, line)
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
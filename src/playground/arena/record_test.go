@@ -0,0 +1,51 @@
+package arena
+
+import (
+	"common"
+	"game"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRecord(t *testing.T) {
+	m := loadMap(t, "testdata/disconnected.json")
+	g, err := NewGameOfBots(&m, game.Settings{FuturesMode: true}, []common.BotSpec{
+		{Name: "scripted:10 11; 11 12"},
+		{Name: "scripted:14 15; 15 16"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := g.Record()
+	if len(r.Moves) != 0 || len(r.Futures) != 2 {
+		t.Errorf("before the game: %v moves, futures of %v punters", len(r.Moves), len(r.Futures))
+	}
+	g.Run()
+	r = g.Record()
+	r.Map = "testdata/disconnected.json"
+	if len(r.Moves) != g.NumMoves() || r.Moves[1].Claim == nil || r.Moves[1].Claim.Source != 14 {
+		t.Errorf("moves: got %v of %v, the second %v", len(r.Moves), g.NumMoves(), r.Moves[1].String())
+	}
+
+	dir, err := ioutil.TempDir("", "record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "records.jsonl")
+	for i := 0; i < 2; i++ {
+		if err := AppendRecord(path, &r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := ReadRecords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || !reflect.DeepEqual(records[1], r) {
+		t.Errorf("read %v records, the last %+v, want %+v", len(records), records[len(records)-1], r)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"math"
+)
+
+// sample is the features of a claim and the final margin of the punter
+// that made it.
+type sample struct {
+	x []float64
+	y float64
+}
+
+// Fits y = w·x + b minimizing the squared errors plus ridge times |w|².
+// The bias is not penalized.
+func fit(samples []sample, ridge float64) (w []float64, b float64, err error) {
+	if len(samples) == 0 {
+		return nil, 0, errors.New("no samples")
+	}
+	n := len(samples[0].x) + 1 // the last column is the bias
+
+	// The normal equations (XᵀX + ridge·I) w = Xᵀy as an augmented matrix.
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n+1)
+	}
+	row := make([]float64, n)
+	for _, s := range samples {
+		copy(row, s.x)
+		row[n-1] = 1
+		for i := range row {
+			for j := range row {
+				a[i][j] += row[i] * row[j]
+			}
+			a[i][n] += row[i] * s.y
+		}
+	}
+	for i := 0; i < n-1; i++ {
+		a[i][i] += ridge
+	}
+
+	// Gaussian elimination with partial pivoting.
+	for c := 0; c < n; c++ {
+		pivot := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(a[r][c]) > math.Abs(a[pivot][c]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][c]) < 1e-12 {
+			return nil, 0, errors.New("the features are linearly dependent, increase --ridge")
+		}
+		a[c], a[pivot] = a[pivot], a[c]
+		for r := 0; r < n; r++ {
+			if r == c {
+				continue
+			}
+			k := a[r][c] / a[c][c]
+			for j := c; j <= n; j++ {
+				a[r][j] -= k * a[c][j]
+			}
+		}
+	}
+	w = make([]float64, n-1)
+	for i := range w {
+		w[i] = a[i][n] / a[i][i]
+	}
+	return w, a[n-1][n] / a[n-1][n-1], nil
+}
+
+// Returns the share of the variance of y the model explains.
+func rSquared(samples []sample, w []float64, b float64) float64 {
+	var mean float64
+	for _, s := range samples {
+		mean += s.y
+	}
+	mean /= float64(len(samples))
+
+	var res, tot float64
+	for _, s := range samples {
+		y := b
+		for i, wi := range w {
+			y += wi * s.x[i]
+		}
+		res += (s.y - y) * (s.y - y)
+		tot += (s.y - mean) * (s.y - mean)
+	}
+	if tot == 0 {
+		return 0
+	}
+	return 1 - res/tot
+}
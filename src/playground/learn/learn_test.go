@@ -0,0 +1,81 @@
+package main
+
+import (
+	"common"
+	"game"
+	"math"
+	"playground/arena"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	// y = 2a - b + 0.5, exactly.
+	var samples []sample
+	for a := 0; a < 4; a++ {
+		for b := 0; b < 3; b++ {
+			x := []float64{float64(a), float64(b)}
+			samples = append(samples, sample{x: x, y: 2*x[0] - x[1] + 0.5})
+		}
+	}
+	w, b, err := fit(samples, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(w[0]-2) > 1e-9 || math.Abs(w[1]+1) > 1e-9 || math.Abs(b-0.5) > 1e-9 {
+		t.Errorf("got w=%v b=%v, want [2 -1] 0.5", w, b)
+	}
+	if r2 := rSquared(samples, w, b); math.Abs(r2-1) > 1e-9 {
+		t.Errorf("R² %v, want 1", r2)
+	}
+
+	// The penalty shrinks the weights.
+	if w, _, _ := fit(samples, 10); !(math.Abs(w[0]) < 2) {
+		t.Errorf("ridge weights %v are not smaller", w)
+	}
+
+	// The same feature twice.
+	var dup []sample
+	for _, s := range samples {
+		dup = append(dup, sample{x: []float64{s.x[0], s.x[0]}, y: s.y})
+	}
+	if _, _, err := fit(dup, 0); err == nil {
+		t.Error("no error for dependent features")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	m, err := common.ReadMap("../arena/testdata/disconnected.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := arena.NewGameOfBots(&m, game.Settings{FuturesMode: true}, []common.BotSpec{{Name: "random1"}, {Name: "baseline"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Run()
+	r := g.Record()
+
+	samples, err := replay(&r, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := 0
+	for _, move := range r.Moves {
+		if move.Claim != nil {
+			claims++
+		}
+	}
+	if len(samples) != claims || claims == 0 {
+		t.Fatalf("%v samples of %v claims", len(samples), claims)
+	}
+	graph := arena.MakeGraph(&m)
+	want := margin(r.Scores, 0) / float64(graph.ScoreUpperBound())
+	if samples[0].y != want {
+		t.Errorf("target of the first claim %v, want %v", samples[0].y, want)
+	}
+	for _, s := range samples {
+		if len(s.x) != len(game.FeatureNames) {
+			t.Fatalf("%v features, want %v", len(s.x), len(game.FeatureNames))
+		}
+	}
+}
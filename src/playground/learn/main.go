@@ -0,0 +1,66 @@
+// The learn command fits the linear model of the learned bot to the
+// records of the games written by the playground with --record.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"game"
+	"log"
+	"path/filepath"
+	"playground/arena"
+	"strings"
+)
+
+var flagRecords = flag.String("records", "records.jsonl", "Comma-separated list of record files, or patterns such as records/*.jsonl")
+var flagOut = flag.String("out", "weights.json", "File to write the model to, for learned:@weights.json")
+var flagRidge = flag.Float64("ridge", 1, "Weight of the penalty on the squared weights")
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+
+	ms := make(maps)
+	var samples []sample
+	games := 0
+	for _, pattern := range strings.Split(*flagRecords, ",") {
+		paths, err := filepath.Glob(pattern)
+		if err != nil || len(paths) == 0 {
+			log.Fatal("No records match ", pattern)
+		}
+		for _, path := range paths {
+			records, err := arena.ReadRecords(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for i := range records {
+				m, err := ms.get(records[i].Map)
+				if err != nil {
+					log.Fatal(err)
+				}
+				s, err := replay(&records[i], m)
+				if err != nil {
+					log.Fatalf("%v: game %v: %v", path, i+1, err)
+				}
+				samples = append(samples, s...)
+			}
+			games += len(records)
+		}
+	}
+	log.Printf("%v claims in %v games on %v maps", len(samples), games, len(ms))
+
+	w, b, err := fit(samples, *flagRidge)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, name := range game.FeatureNames {
+		fmt.Printf("%-10v %10.6f\n", name, w[i])
+	}
+	fmt.Printf("%-10v %10.6f\n", "bias", b)
+	fmt.Printf("R² %.4f\n", rSquared(samples, w, b))
+
+	model := game.LinearModel{Features: game.FeatureNames, Weights: w, Bias: b}
+	if err := model.Save(*flagOut); err != nil {
+		log.Fatal(err)
+	}
+}
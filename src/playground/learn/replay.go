@@ -0,0 +1,107 @@
+package main
+
+import (
+	"common"
+	"fmt"
+	"game"
+	"playground/arena"
+)
+
+// Caches the maps of the records by their paths.
+type maps map[string]*common.Map
+
+func (ms maps) get(path string) (*common.Map, error) {
+	if m, ok := ms[path]; ok {
+		return m, nil
+	}
+	m, err := common.ReadMap(path)
+	if err != nil {
+		return nil, err
+	}
+	ms[path] = &m
+	return &m, nil
+}
+
+// Replays the game and returns a sample for every claim: the features
+// of the river in the position before the claim, as the punter who made
+// it saw them, and the final margin of that punter.
+//
+// The margin depends on the position much more than on the claim, so the
+// features are taken relative to the mean over the rivers the learned bot
+// would choose from. This doesn't change its choice.
+func replay(r *arena.Record, m *common.Map) (samples []sample, err error) {
+	g := arena.MakeGraph(m)
+	p := &game.BaselinePlayer{Graph: g.Graph, Punters: len(r.Scores), Settings: r.Settings}
+	scale := float64(p.ScoreUpperBound())
+	if scale <= 0 {
+		scale = 1
+	}
+
+	futures := make([][]game.Future, p.Punters)
+	for q, fs := range r.Futures {
+		if q >= p.Punters {
+			return nil, fmt.Errorf("futures of %v punters in a game of %v", len(r.Futures), p.Punters)
+		}
+		for _, f := range fs {
+			futures[q] = append(futures[q], game.Future{Src: g.Index.Forward[f.Src], Dst: g.Index.Forward[f.Dst]})
+		}
+	}
+
+	for i := range r.Moves {
+		move := g.Index.GameMove(&r.Moves[i])
+		if move.Type == game.Claim {
+			if move.Punter < 0 || move.Punter >= p.Punters {
+				return nil, fmt.Errorf("move %v: no punter %v", i, move.Punter)
+			}
+			p.Punter = move.Punter
+			p.Futures = futures[move.Punter]
+			p.PrepareForMove(nil)
+			e := freeRiver(&p.Graph, move.Source, move.Target)
+			if e == nil {
+				return nil, fmt.Errorf("move %v: %v is not a free river", i, r.Moves[i].String())
+			}
+			samples = append(samples, sample{x: relative(p, e), y: margin(r.Scores, move.Punter) / scale})
+		}
+		p.ApplyMoves([]game.Move{move})
+	}
+	return samples, nil
+}
+
+// Returns the features of the claim of e less their mean over the free
+// rivers next to the punter's network.
+func relative(p *game.BaselinePlayer, e *game.Edge) []float64 {
+	f := game.MakeFeatures(p)
+	x := f.Of(e)
+	rivers := p.FreeRiversNearby()
+	mean := make([]float64, len(x))
+	for _, r := range rivers {
+		for i, v := range f.Of(r) {
+			mean[i] += v / float64(len(rivers))
+		}
+	}
+	for i := range x {
+		x[i] -= mean[i]
+	}
+	return x
+}
+
+// Returns the free river between sites u and v, nil if there is none.
+func freeRiver(g *game.Graph, u, v int) *game.Edge {
+	for _, eId := range g.Edges[u] {
+		if e := &g.AllEdges[eId]; e.Dst == v && e.Owner < 0 {
+			return e
+		}
+	}
+	return nil
+}
+
+// Returns the score of the punter less the best of the others.
+func margin(scores []int64, punter int) float64 {
+	best, first := int64(0), true
+	for q, s := range scores {
+		if q != punter && (first || s > best) {
+			best, first = s, false
+		}
+	}
+	return float64(scores[punter] - best)
+}
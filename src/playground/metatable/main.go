@@ -0,0 +1,226 @@
+// The metatable command writes the selection table of the meta bot: the
+// bot with the best mean score margin in every kind of game among the
+// records written by the playground with --record.
+package main
+
+import (
+	"common"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"game"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"playground/arena"
+	"sort"
+	"strings"
+)
+
+var flagRecords = flag.String("records", "records.jsonl", "Comma-separated list of record files, or patterns such as records/*.jsonl")
+var flagOut = flag.String("out", "table.json", "File to write the table to, for meta:@table.json")
+var flagMinGames = flag.Int("min-games", 2, "Games a bot must play in a kind of game to be chosen for it")
+var flagGo = flag.Bool("go", false, "Print the table as Go code for src/game/meta_table.go")
+
+// The kind of game: the map and the settings with the number of punters.
+type kind struct {
+	path     string
+	settings game.Settings
+	punters  int
+}
+
+// The games of a bot in a kind of game.
+type stats struct {
+	bot   common.BotSpec
+	sum   float64
+	games int
+}
+
+func (s *stats) mean() float64 { return s.sum / float64(s.games) }
+
+// Scores the games: every bot gets its score less the best of the others
+// as a share of the score upper bound. The bots are told apart by their
+// parameters, and the meta bots are left out, since the meta bot can't
+// choose them.
+type results struct {
+	features map[kind]game.GameFeatures
+	bots     map[kind]map[string]*stats // by the bot spec
+}
+
+func makeResults() results {
+	return results{make(map[kind]game.GameFeatures), make(map[kind]map[string]*stats)}
+}
+
+func (rs *results) add(r *arena.Record, f game.GameFeatures, upper int64) {
+	k := kind{r.Map, r.Settings, len(r.Scores)}
+	rs.features[k] = f
+	if rs.bots[k] == nil {
+		rs.bots[k] = make(map[string]*stats)
+	}
+	for q, bot := range r.Bots {
+		if bot.Name == "meta" || strings.HasPrefix(bot.Name, game.MetaPrefix) {
+			continue
+		}
+		best, first := int64(0), true
+		for o, s := range r.Scores {
+			if o != q && (first || s > best) {
+				best, first = s, false
+			}
+		}
+		s := rs.bots[k][bot.String()]
+		if s == nil {
+			s = &stats{bot: bot}
+			rs.bots[k][bot.String()] = s
+		}
+		s.sum += float64(r.Scores[q]-best) / float64(upper)
+		s.games++
+	}
+}
+
+// Returns the best bot of every kind of game, ordered by the features.
+func (rs *results) table(minGames int) (t game.SelectionTable) {
+	for k, bots := range rs.bots {
+		var best game.Selection
+		bestSpec := ""
+		for spec, s := range bots {
+			if s.games < minGames {
+				continue
+			}
+			if best.Bot == "" || s.mean() > best.Margin || s.mean() == best.Margin && spec < bestSpec {
+				best = game.Selection{GameFeatures: rs.features[k], Bot: s.bot.Name, Params: s.bot.Params, Margin: s.mean(), Games: s.games}
+				bestSpec = spec
+			}
+		}
+		if best.Bot != "" {
+			t = append(t, best)
+		}
+	}
+	sort.Slice(t, func(i, j int) bool {
+		a, b := order(&t[i].GameFeatures), order(&t[j].GameFeatures)
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return
+}
+
+// The table is ordered by the size of the map, then by the punters and
+// the settings.
+func order(f *game.GameFeatures) []int {
+	flag := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	return []int{f.Rivers, f.Sites, f.Mines, f.Punters, flag(f.Futures), flag(f.Splurges), flag(f.Options)}
+}
+
+func goCode(t game.SelectionTable) string {
+	var b strings.Builder
+	b.WriteString("var defaultSelection = SelectionTable{\n")
+	for _, s := range t {
+		f := s.GameFeatures
+		fmt.Fprintf(&b, "\t{GameFeatures: GameFeatures{Sites: %v, Rivers: %v, Mines: %v, Punters: %v", f.Sites, f.Rivers, f.Mines, f.Punters)
+		if f.Futures {
+			b.WriteString(", Futures: true")
+		}
+		if f.Splurges {
+			b.WriteString(", Splurges: true")
+		}
+		if f.Options {
+			b.WriteString(", Options: true")
+		}
+		fmt.Fprintf(&b, "}, Bot: %q", s.Bot)
+		if len(s.Params) > 0 {
+			names := make([]string, 0, len(s.Params))
+			for name := range s.Params {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			b.WriteString(", Params: map[string]float64{")
+			for i, name := range names {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				fmt.Fprintf(&b, "%q: %v", name, s.Params[name])
+			}
+			b.WriteString("}")
+		}
+		fmt.Fprintf(&b, ", Margin: %.4f, Games: %v},\n", s.Margin, s.Games)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+type mapInfo struct {
+	m     game.Map
+	upper int64 // the score upper bound, at least 1
+}
+
+func loadMap(path string) (*mapInfo, error) {
+	m, err := common.ReadMap(path)
+	if err != nil {
+		return nil, err
+	}
+	g := arena.MakeGraph(&m)
+	info := &mapInfo{m: common.MakeGameMap(&m, &g.Index), upper: g.ScoreUpperBound()}
+	if info.upper <= 0 {
+		info.upper = 1
+	}
+	return info, nil
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+
+	maps := make(map[string]*mapInfo)
+	rs := makeResults()
+	games := 0
+	for _, pattern := range strings.Split(*flagRecords, ",") {
+		paths, err := filepath.Glob(pattern)
+		if err != nil || len(paths) == 0 {
+			log.Fatal("No records match ", pattern)
+		}
+		for _, path := range paths {
+			records, err := arena.ReadRecords(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for i := range records {
+				r := &records[i]
+				info := maps[r.Map]
+				if info == nil {
+					if info, err = loadMap(r.Map); err != nil {
+						log.Fatal(err)
+					}
+					maps[r.Map] = info
+				}
+				rs.add(r, game.MakeGameFeatures(len(r.Scores), info.m, r.Settings), info.upper)
+			}
+			games += len(records)
+		}
+	}
+
+	t := rs.table(*flagMinGames)
+	log.Printf("%v kinds of games in %v games on %v maps", len(t), games, len(maps))
+	if *flagGo {
+		fmt.Print(goCode(t))
+	} else {
+		for _, s := range t {
+			fmt.Printf("%+v: %v %.4f over %v games\n", s.GameFeatures, common.BotSpec{Name: s.Bot, Params: s.Params}, s.Margin, s.Games)
+		}
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*flagOut, append(data, '\n'), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
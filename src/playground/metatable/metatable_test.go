@@ -0,0 +1,51 @@
+package main
+
+import (
+	"common"
+	"game"
+	"playground/arena"
+	"strings"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	small := game.GameFeatures{Sites: 8, Rivers: 6, Mines: 4, Punters: 2}
+	large := game.GameFeatures{Sites: 100, Rivers: 200, Mines: 4, Punters: 2}
+	rs := makeResults()
+	m, random1 := common.BotSpec{Name: "m"}, common.BotSpec{Name: "random1", Params: map[string]float64{"depth": 6}}
+	meta := common.BotSpec{Name: "meta"}
+	rs.add(&arena.Record{Map: "a", Bots: []common.BotSpec{m, random1}, Scores: []int64{10, 5}}, small, 10)
+	rs.add(&arena.Record{Map: "a", Bots: []common.BotSpec{random1, m}, Scores: []int64{6, 2}}, small, 10)
+	rs.add(&arena.Record{Map: "b", Bots: []common.BotSpec{m, random1, meta}, Scores: []int64{50, 10, 0}}, large, 100)
+
+	// On map a m has the margins 0.5 and -0.4, random1 -0.5 and 0.4. The
+	// meta bot isn't in the table.
+	table := rs.table(1)
+	if len(table) != 2 || table[0].GameFeatures != small || table[1].GameFeatures != large {
+		t.Fatalf("got %+v", table)
+	}
+	if s := table[0]; s.Bot != "m" || s.Games != 2 || s.Margin < 0.0499 || s.Margin > 0.0501 {
+		t.Errorf("map a: got %+v, want m 0.05 over 2 games", s)
+	}
+	if s := table[1]; s.Bot != "m" || s.Margin != 0.4 {
+		t.Errorf("map b: got %+v, want m 0.4", s)
+	}
+	if table := rs.table(2); len(table) != 1 {
+		t.Errorf("%v kinds of games with 2 games, want 1", len(table))
+	}
+
+	code := goCode(table)
+	if !strings.Contains(code, `{GameFeatures: GameFeatures{Sites: 8, Rivers: 6, Mines: 4, Punters: 2}, Bot: "m", Margin: 0.0500, Games: 2},`) {
+		t.Errorf("got code\n%v", code)
+	}
+
+	// The bot is chosen with its parameters.
+	rs.add(&arena.Record{Map: "a", Bots: []common.BotSpec{random1, m}, Scores: []int64{9, 2}}, small, 10)
+	table = rs.table(1)
+	if s := table[0]; s.Bot != "random1" || s.Params["depth"] != 6 {
+		t.Errorf("map a: got %+v, want random1 with depth 6", s)
+	}
+	if code := goCode(table); !strings.Contains(code, `Bot: "random1", Params: map[string]float64{"depth": 6}, Margin: 0.2000, Games: 3},`) {
+		t.Errorf("got code\n%v", code)
+	}
+}
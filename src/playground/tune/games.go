@@ -0,0 +1,147 @@
+package main
+
+import (
+	"common"
+	"game"
+	"playground/arena"
+	"sync"
+	"time"
+)
+
+// gameObjective plays the tuned bot against the lineups of opponents on
+// the maps. Game i is played on map i mod len(maps), against the lineup
+// (i / len(maps)) mod len(lineups), with the seed and the seat of the
+// tuned bot growing with every round over all maps and lineups. The seed
+// goes to every bot that has the seed parameter, unless it is given.
+//
+// The outcome of a game is the score of the tuned bot less the best score
+// of the others, as a share of the score upper bound of the map.
+type gameObjective struct {
+	bot      common.BotSpec
+	space    space
+	maps     []*common.Map
+	lineups  [][]common.BotSpec
+	settings game.Settings
+	rules    arena.Rules
+	timeout  time.Duration
+	workers  int
+
+	seeded map[string]bool // the bots that declare the seed parameter
+	bounds []int64         // the score upper bound of every map
+}
+
+func makeGameObjective(bot common.BotSpec, sp space, maps []*common.Map, lineups [][]common.BotSpec) *gameObjective {
+	o := &gameObjective{bot: bot, space: sp, maps: maps, lineups: lineups, workers: 1, seeded: make(map[string]bool)}
+	for _, m := range maps {
+		g := arena.MakeGraph(m)
+		o.bounds = append(o.bounds, g.ScoreUpperBound())
+	}
+	check := func(b common.BotSpec) {
+		if p, err := game.MakePlayerWithParams(b.Name, nil); err == nil {
+			for _, param := range game.PlayerParams(p) {
+				if param.Name == "seed" {
+					o.seeded[b.Name] = true
+				}
+			}
+		}
+	}
+	check(bot)
+	for _, l := range lineups {
+		for _, b := range l {
+			check(b)
+		}
+	}
+	return o
+}
+
+// Returns the bot with the seed, keeping the parameters it was given.
+func (o *gameObjective) withSeed(b common.BotSpec, seed int) common.BotSpec {
+	if _, given := b.Params["seed"]; given || !o.seeded[b.Name] {
+		return b
+	}
+	params := map[string]float64{"seed": float64(seed)}
+	for k, v := range b.Params {
+		params[k] = v
+	}
+	return common.BotSpec{Name: b.Name, Params: params}
+}
+
+// Returns the map, the bots and the seat of the tuned bot of game i.
+func (o *gameObjective) setup(i int, values map[string]float64) (mapIndex int, bots []common.BotSpec, seat int) {
+	mapIndex = i % len(o.maps)
+	round := i / len(o.maps)
+	lineup := o.lineups[round%len(o.lineups)]
+	seed := round / len(o.lineups)
+	seat = seed % (len(lineup) + 1)
+
+	tuned := common.BotSpec{Name: o.bot.Name, Params: make(map[string]float64)}
+	for k, v := range o.bot.Params {
+		tuned.Params[k] = v
+	}
+	for k, v := range values {
+		tuned.Params[k] = v
+	}
+	for _, b := range lineup[:seat] {
+		bots = append(bots, o.withSeed(b, seed))
+	}
+	bots = append(bots, o.withSeed(tuned, seed))
+	for _, b := range lineup[seat:] {
+		bots = append(bots, o.withSeed(b, seed))
+	}
+	return
+}
+
+func (o *gameObjective) playGame(i int, values map[string]float64) (float64, error) {
+	mi, bots, seat := o.setup(i, values)
+	g, err := arena.NewGameOfBots(o.maps[mi], o.settings, bots)
+	if err != nil {
+		return 0, err
+	}
+	g.Rules = o.rules
+	g.Timeout = o.timeout
+	r := g.Run()
+
+	best, first := int64(0), true
+	for p, s := range r.Scores {
+		if p != seat && (first || s > best) {
+			best, first = s, false
+		}
+	}
+	bound := o.bounds[mi]
+	if bound <= 0 {
+		bound = 1
+	}
+	return float64(r.Scores[seat]-best) / float64(bound), nil
+}
+
+func (o *gameObjective) play(c *candidate, games int) {
+	values := o.space.values(c.x)
+	first := c.first + len(c.results)
+	results := make([]float64, games)
+	errs := make([]error, games)
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range next {
+				results[k], errs[k] = o.playGame(first+k, values)
+			}
+		}()
+	}
+	for k := 0; k < games; k++ {
+		next <- k
+	}
+	close(next)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			// The bots and parameters are checked before the search.
+			panic(err.Error())
+		}
+	}
+	c.results = append(c.results, results...)
+}
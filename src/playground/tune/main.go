@@ -0,0 +1,133 @@
+// The tune command searches for the parameters of a bot that win the most
+// against the given opponents on the given maps.
+package main
+
+import (
+	"common"
+	"flag"
+	"fmt"
+	"game"
+	"log"
+	"math/rand"
+	"playground/arena"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var flagBot = flag.String("bot", "random1", "Bot to tune, with the parameters that stay fixed as in random1(opening=2)")
+var flagSpace = flag.String("space", "", "Ranges of the tuned parameters, e.g. depth=2:12,discount=0.8:1; all parameters of the bot within their bounds by default")
+var flagMaps = flag.String("maps", "maps/lambda.json,maps/circle.json", "Comma-separated list of maps, or patterns such as maps/*.json")
+var flagOpponents = flag.String("opponents", "baseline", "Lineups of opponents separated by semicolons, e.g. random1*2;baseline,m")
+var flagSettings = flag.String("settings", "", "Comma-separated list of settings: futures, splurges, options")
+var flagRules = flag.String("rules", "official", "Game rules: official or legacy")
+var flagTimeout = flag.Duration("timeout", 0, "Time limit of a move, 0 for none")
+var flagMethod = flag.String("method", "random", "Search method: random, halving or cmaes")
+var flagCandidates = flag.Int("candidates", 20, "Number of parameter sets to try")
+var flagGames = flag.Int("games", 8, "Games of every parameter set, the first round of them for halving")
+var flagFinal = flag.Int("final", 32, "Fresh games of the best and the default parameters for the confidence bounds")
+var flagSeed = flag.Int64("seed", 1, "Seed of the search")
+var flagWorkers = flag.Int("workers", runtime.NumCPU(), "Games played at the same time")
+var flagVerbose = flag.Bool("v", false, "Show every candidate")
+
+func parseLineups(s string) (lineups [][]common.BotSpec) {
+	for _, part := range strings.Split(s, ";") {
+		bots, err := common.ParseBotSpecs(part)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, b := range bots {
+			if _, err := game.MakePlayerWithParams(b.Name, b.Params); err != nil {
+				log.Fatal(err)
+			}
+		}
+		lineups = append(lineups, bots)
+	}
+	return
+}
+
+func (sp space) format(bot common.BotSpec, x []float64) string {
+	params := sp.values(x)
+	for k, v := range bot.Params {
+		params[k] = v
+	}
+	return common.BotSpec{Name: bot.Name, Params: params}.String()
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+
+	bot, err := common.ParseBotSpec(*flagBot)
+	if err != nil {
+		log.Fatal(err)
+	}
+	p, err := game.MakePlayerWithParams(bot.Name, bot.Params)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var params []game.Param
+	for _, param := range game.PlayerParams(p) {
+		if _, fixed := bot.Params[param.Name]; !fixed && param.Name != "seed" {
+			params = append(params, param)
+		}
+	}
+	sp, err := parseSpace(*flagSpace, params)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(sp) == 0 {
+		log.Fatal("Nothing to tune: ", bot.Name, " has no free parameters")
+	}
+	method, err := methodByName(*flagMethod)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rules, err := arena.ParseRules(*flagRules)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	maps, err := arena.LoadMaps(*flagMaps)
+	if err != nil {
+		log.Fatal(err)
+	}
+	obj := makeGameObjective(bot, sp, maps, parseLineups(*flagOpponents))
+	if obj.settings, err = arena.ParseSettings(*flagSettings); err != nil {
+		log.Fatal(err)
+	}
+	obj.rules = rules
+	obj.timeout = *flagTimeout
+	obj.workers = *flagWorkers
+
+	for _, d := range sp {
+		log.Printf("Tuning %v in [%v, %v], default %v", d.name, d.min, d.max, d.def)
+	}
+	start := time.Now()
+	cs := method(obj, sp.defaults(), *flagCandidates, *flagGames, rand.New(rand.NewSource(*flagSeed)))
+	log.Printf("Tried %v candidates with %v in %v", len(cs), *flagMethod, time.Since(start).Round(time.Second))
+
+	if *flagVerbose {
+		for _, c := range cs {
+			fmt.Printf("%v: %.4f ± %.4f over %v games\n", sp.format(bot, c.x), c.mean(), c.bound(), len(c.results))
+		}
+	}
+
+	// The games of the search favour the lucky candidates, the bounds come
+	// from the games none of them has played.
+	fresh := len(cs[0].results)
+	for _, c := range cs {
+		if len(c.results) > fresh {
+			fresh = len(c.results)
+		}
+	}
+	best := &candidate{x: cs[0].x, first: fresh}
+	defaults := &candidate{x: sp.defaults(), first: fresh}
+	obj.play(best, *flagFinal)
+	obj.play(defaults, *flagFinal)
+
+	fmt.Printf("Best: %v\n", sp.format(bot, best.x))
+	fmt.Printf("  score margin %.4f ± %.4f (95%%) over %v fresh games\n", best.mean(), best.bound(), len(best.results))
+	fmt.Printf("Defaults: %v\n", sp.format(bot, defaults.x))
+	fmt.Printf("  score margin %.4f ± %.4f (95%%) over the same games\n", defaults.mean(), defaults.bound())
+}
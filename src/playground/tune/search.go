@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// candidate is a point of the parameter space with the outcomes of the
+// games it has played.
+type candidate struct {
+	x       []float64 // coordinates in [0..1]
+	first   int       // the index of its first game
+	results []float64 // the outcome of every game, higher is better
+}
+
+func (c *candidate) mean() float64 {
+	if len(c.results) == 0 {
+		return math.Inf(-1)
+	}
+	sum := 0.0
+	for _, r := range c.results {
+		sum += r
+	}
+	return sum / float64(len(c.results))
+}
+
+// Returns the half-width of the 95% confidence interval of the mean.
+func (c *candidate) bound() float64 {
+	n := float64(len(c.results))
+	if n < 2 {
+		return math.Inf(1)
+	}
+	m, ss := c.mean(), 0.0
+	for _, r := range c.results {
+		ss += (r - m) * (r - m)
+	}
+	return 1.96 * math.Sqrt(ss/(n-1)/n)
+}
+
+// objective plays more games of the candidate. The i-th game of every
+// candidate is the same but for the candidate's parameters, so that the
+// candidates are compared on equal terms.
+type objective interface {
+	play(c *candidate, games int)
+}
+
+// Plays every candidate until it has the given number of games.
+func playAll(obj objective, cs []*candidate, games int) {
+	for _, c := range cs {
+		if n := games - len(c.results); n > 0 {
+			obj.play(c, n)
+		}
+	}
+}
+
+// Sorts the candidates by the number of games, then by the mean.
+func rank(cs []*candidate) {
+	sort.SliceStable(cs, func(a, b int) bool {
+		if len(cs[a].results) != len(cs[b].results) {
+			return len(cs[a].results) > len(cs[b].results)
+		}
+		return cs[a].mean() > cs[b].mean()
+	})
+}
+
+func uniform(dims int, r *rand.Rand) []float64 {
+	x := make([]float64, dims)
+	for i := range x {
+		x[i] = r.Float64()
+	}
+	return x
+}
+
+// searchMethod tries the given number of candidates, starting from the
+// default values x0, and returns all of them, the best first.
+type searchMethod func(obj objective, x0 []float64, candidates, games int, r *rand.Rand) []*candidate
+
+func methodByName(name string) (searchMethod, error) {
+	switch name {
+	case "random":
+		return randomSearch, nil
+	case "halving":
+		return successiveHalving, nil
+	case "cmaes":
+		return cmaes, nil
+	}
+	return nil, fmt.Errorf("unknown search method: %v", name)
+}
+
+// Plays the games of the default values and of uniformly random points.
+func randomSearch(obj objective, x0 []float64, candidates, games int, r *rand.Rand) []*candidate {
+	cs := []*candidate{{x: x0}}
+	for len(cs) < candidates {
+		cs = append(cs, &candidate{x: uniform(len(x0), r)})
+	}
+	playAll(obj, cs, games)
+	rank(cs)
+	return cs
+}
+
+// Starts with the default values and random points, plays the given
+// number of games of all of them and drops the worse half, doubling the
+// games of the rest every round until one is left.
+func successiveHalving(obj objective, x0 []float64, candidates, games int, r *rand.Rand) []*candidate {
+	cs := []*candidate{{x: x0}}
+	for len(cs) < candidates {
+		cs = append(cs, &candidate{x: uniform(len(x0), r)})
+	}
+	all := append([]*candidate(nil), cs...)
+	for n := games; ; n *= 2 {
+		playAll(obj, cs, n)
+		rank(cs)
+		if len(cs) == 1 {
+			break
+		}
+		cs = cs[:(len(cs)+1)/2]
+	}
+	rank(all)
+	return all
+}
+
+// CMA-ES with the covariance matrix kept as its Cholesky factor, see
+// Hansen, "The CMA Evolution Strategy: A Tutorial". The generations of
+// lambda points are sampled around the mean moving to the best ones.
+func cmaes(obj objective, x0 []float64, candidates, games int, r *rand.Rand) []*candidate {
+	n := len(x0)
+	lambda := 4 + int(3*math.Log(float64(n)))
+	mu := lambda / 2
+
+	w := make([]float64, mu)
+	sum, sum2 := 0.0, 0.0
+	for i := range w {
+		w[i] = math.Log(float64(mu)+0.5) - math.Log(float64(i+1))
+		sum += w[i]
+	}
+	for i := range w {
+		w[i] /= sum
+		sum2 += w[i] * w[i]
+	}
+	mueff := 1 / sum2
+
+	fn := float64(n)
+	cs := (mueff + 2) / (fn + mueff + 5)
+	ds := 1 + 2*math.Max(0, math.Sqrt((mueff-1)/(fn+1))-1) + cs
+	cc := (4 + mueff/fn) / (fn + 4 + 2*mueff/fn)
+	c1 := 2 / ((fn+1.3)*(fn+1.3) + mueff)
+	cmu := math.Min(1-c1, 2*(mueff-2+1/mueff)/((fn+2)*(fn+2)+mueff))
+	chiN := math.Sqrt(fn) * (1 - 1/(4*fn) + 1/(21*fn*fn))
+
+	mean := append([]float64(nil), x0...)
+	sigma := 0.3
+	c := identity(n)
+	ps := make([]float64, n)
+	pc := make([]float64, n)
+
+	all := []*candidate{{x: x0}}
+	playAll(obj, all, games)
+	for gen := 0; len(all) < candidates; gen++ {
+		a := cholesky(c)
+		type sample struct {
+			z, y []float64
+			c    *candidate
+		}
+		samples := make([]sample, lambda)
+		if left := candidates - len(all); left < lambda {
+			samples = samples[:left] // the last generation is only played
+		}
+		for k := range samples {
+			s := &samples[k]
+			s.z = make([]float64, n)
+			for i := range s.z {
+				s.z[i] = r.NormFloat64()
+			}
+			s.y = mulVec(a, s.z)
+			x := make([]float64, n)
+			for i := range x {
+				x[i] = math.Max(0, math.Min(1, mean[i]+sigma*s.y[i]))
+			}
+			s.c = &candidate{x: x}
+			obj.play(s.c, games)
+			all = append(all, s.c)
+		}
+		if len(samples) < lambda {
+			break
+		}
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].c.mean() > samples[j].c.mean() })
+
+		yw := make([]float64, n)
+		zw := make([]float64, n)
+		for k := 0; k < mu; k++ {
+			for i := 0; i < n; i++ {
+				yw[i] += w[k] * samples[k].y[i]
+				zw[i] += w[k] * samples[k].z[i]
+			}
+		}
+		for i := range mean {
+			mean[i] += sigma * yw[i]
+		}
+
+		norm := 0.0
+		for i := range ps {
+			ps[i] = (1-cs)*ps[i] + math.Sqrt(cs*(2-cs)*mueff)*zw[i]
+			norm += ps[i] * ps[i]
+		}
+		norm = math.Sqrt(norm)
+		hs := 0.0
+		if norm/math.Sqrt(1-math.Pow(1-cs, 2*float64(gen+1))) < (1.4+2/(fn+1))*chiN {
+			hs = 1
+		}
+		for i := range pc {
+			pc[i] = (1-cc)*pc[i] + hs*math.Sqrt(cc*(2-cc)*mueff)*yw[i]
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				v := (1-c1-cmu)*c[i][j] + c1*(pc[i]*pc[j]+(1-hs)*cc*(2-cc)*c[i][j])
+				for k := 0; k < mu; k++ {
+					v += cmu * w[k] * samples[k].y[i] * samples[k].y[j]
+				}
+				c[i][j] = v
+			}
+		}
+		sigma *= math.Exp(cs / ds * (norm/chiN - 1))
+	}
+	rank(all)
+	return all
+}
+
+func identity(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		m[i][i] = 1
+	}
+	return m
+}
+
+// Returns the lower triangular l with l*l^T = m. A matrix that is not
+// positive definite because of rounding is regularized.
+func cholesky(m [][]float64) [][]float64 {
+	n := len(m)
+	for eps := 0.0; ; eps = math.Max(1e-10, 10*eps) {
+		l := make([][]float64, n)
+		ok := true
+		for i := 0; i < n && ok; i++ {
+			l[i] = make([]float64, n)
+			for j := 0; j <= i; j++ {
+				s := m[i][j]
+				if i == j {
+					s += eps
+				}
+				for k := 0; k < j; k++ {
+					s -= l[i][k] * l[j][k]
+				}
+				if i == j {
+					if s <= 0 {
+						ok = false
+						break
+					}
+					l[i][i] = math.Sqrt(s)
+				} else {
+					l[i][j] = s / l[j][j]
+				}
+			}
+		}
+		if ok {
+			return l
+		}
+	}
+}
+
+func mulVec(m [][]float64, v []float64) []float64 {
+	r := make([]float64, len(m))
+	for i := range m {
+		for j, x := range v {
+			r[i] += m[i][j] * x
+		}
+	}
+	return r
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"game"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// dim is a parameter being tuned. The search works on coordinates from
+// [0..1] that are mapped onto [min..max].
+type dim struct {
+	name     string
+	min, max float64
+	integer  bool // the values are rounded to integers
+	def      float64
+}
+
+type space []dim
+
+// Parses the ranges of the parameters, e.g. depth=2:12,discount=0.8:1.
+// Without ranges, every parameter the bot declares is tuned within its
+// bounds. The ranges must be within the bounds too.
+func parseSpace(s string, params []game.Param) (sp space, err error) {
+	declared := make(map[string]game.Param)
+	for _, p := range params {
+		declared[p.Name] = p
+	}
+
+	if s == "" {
+		for _, p := range params {
+			sp = append(sp, dim{name: p.Name, min: p.Min, max: p.Max, integer: p.Integer, def: p.Default})
+		}
+		return sp, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		kv := strings.Split(part, "=")
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("bad parameter range: %q", part)
+		}
+		d := dim{name: strings.TrimSpace(kv[0])}
+		p, ok := declared[d.name]
+		if !ok {
+			return nil, fmt.Errorf("the bot has no parameter %v", d.name)
+		}
+		bounds := strings.Split(kv[1], ":")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("bad range of parameter %v: %q", d.name, kv[1])
+		}
+		if d.min, err = strconv.ParseFloat(strings.TrimSpace(bounds[0]), 64); err == nil {
+			d.max, err = strconv.ParseFloat(strings.TrimSpace(bounds[1]), 64)
+		}
+		if err != nil || d.min >= d.max {
+			return nil, fmt.Errorf("bad range of parameter %v: %q", d.name, kv[1])
+		}
+		if d.min < p.Min || d.max > p.Max {
+			return nil, fmt.Errorf("range of parameter %v is out of its bounds [%v, %v]", d.name, p.Min, p.Max)
+		}
+		d.def = math.Max(d.min, math.Min(d.max, p.Default))
+		d.integer = p.Integer
+		sp = append(sp, d)
+	}
+	return sp, nil
+}
+
+// Returns the value of the coordinate u, rounded to about a thousandth
+// of the range.
+func (d *dim) value(u float64) float64 {
+	v := d.min + math.Max(0, math.Min(1, u))*(d.max-d.min)
+	if d.integer {
+		return math.Round(v)
+	}
+	scale := math.Pow(10, math.Ceil(-math.Log10((d.max-d.min)/1000)))
+	return math.Round(v*scale) / scale
+}
+
+// Returns the coordinates of the default values.
+func (sp space) defaults() []float64 {
+	x := make([]float64, len(sp))
+	for i, d := range sp {
+		x[i] = (d.def - d.min) / (d.max - d.min)
+	}
+	return x
+}
+
+// Returns the parameter values at the coordinates x.
+func (sp space) values(x []float64) map[string]float64 {
+	values := make(map[string]float64, len(sp))
+	for i := range sp {
+		values[sp[i].name] = sp[i].value(x[i])
+	}
+	return values
+}
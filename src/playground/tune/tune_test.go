@@ -0,0 +1,131 @@
+package main
+
+import (
+	"common"
+	"game"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParseSpace(t *testing.T) {
+	params := []game.Param{
+		{Name: "depth", Default: 10, Min: 1, Max: 30, Integer: true},
+		{Name: "discount", Default: 0.95, Min: 0, Max: 1},
+		{Name: "opening", Default: 0, Min: 0, Max: 10, Integer: true},
+	}
+
+	sp, err := parseSpace("", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := space{
+		{name: "depth", min: 1, max: 30, integer: true, def: 10},
+		{name: "discount", min: 0, max: 1, def: 0.95},
+		{name: "opening", min: 0, max: 10, integer: true, def: 0},
+	}
+	if !reflect.DeepEqual(sp, want) {
+		t.Errorf("default space: got %+v, want %+v", sp, want)
+	}
+
+	sp, err = parseSpace("depth=2:12,discount=0.8:1", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := sp.values([]float64{0.55, 0.5})
+	if wantValues := map[string]float64{"depth": 8, "discount": 0.9}; !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("values: got %v, want %v", values, wantValues)
+	}
+	if x := sp.defaults(); x[0] != 0.8 || math.Abs(x[1]-0.75) > 1e-9 {
+		t.Errorf("defaults: got %v", x)
+	}
+
+	for _, bad := range []string{"depth", "depth=2", "depth=3:2", "nonsense=1:2", "discount=0.5:1.5"} {
+		if _, err := parseSpace(bad, params); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
+
+// The outcome of every game is the same, the closer to (0.7, 0.2) the
+// better.
+type quadratic struct{ games int }
+
+func (q *quadratic) play(c *candidate, games int) {
+	v := -(c.x[0]-0.7)*(c.x[0]-0.7) - (c.x[1]-0.2)*(c.x[1]-0.2)
+	for i := 0; i < games; i++ {
+		c.results = append(c.results, v)
+	}
+	q.games += games
+}
+
+func TestSearchMethods(t *testing.T) {
+	x0 := []float64{0.5, 0.5}
+	for _, name := range []string{"random", "halving", "cmaes"} {
+		method, err := methodByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q := &quadratic{}
+		cs := method(q, x0, 60, 2, rand.New(rand.NewSource(1)))
+		if len(cs) != 60 {
+			t.Errorf("%v: got %v candidates, want 60", name, len(cs))
+		}
+		if few := method(&quadratic{}, x0, 4, 2, rand.New(rand.NewSource(1))); len(few) != 4 {
+			t.Errorf("%v: got %v candidates, want 4", name, len(few))
+		}
+		if d := -cs[0].mean(); d > 0.01 {
+			t.Errorf("%v: the best %v is too far from the optimum", name, cs[0].x)
+		}
+		for _, c := range cs {
+			if len(c.results) > len(cs[0].results) {
+				t.Errorf("%v: the best candidate has fewer games than %v", name, c.x)
+			}
+		}
+	}
+	if _, err := methodByName("nonsense"); err == nil {
+		t.Error("unknown method: no error")
+	}
+}
+
+func TestCandidateBound(t *testing.T) {
+	c := &candidate{results: []float64{1, 2, 3, 4}}
+	if c.mean() != 2.5 {
+		t.Errorf("mean: got %v, want 2.5", c.mean())
+	}
+	if b := c.bound(); math.Abs(b-1.96*math.Sqrt(5.0/3/4)) > 1e-9 {
+		t.Errorf("bound: got %v", b)
+	}
+}
+
+func TestGameSetup(t *testing.T) {
+	o := makeGameObjective(common.BotSpec{Name: "random1"}, space{{name: "depth", min: 2, max: 12, integer: true}},
+		[]*common.Map{{}, {}}, [][]common.BotSpec{{{Name: "baseline"}}, {{Name: "random0", Params: map[string]float64{"seed": 7}}, {Name: "m"}}})
+
+	// Maps first, then lineups, then seeds and seats.
+	tests := []struct {
+		game, mapIndex, seat int
+		bots                 []common.BotSpec
+	}{
+		{0, 0, 0, []common.BotSpec{
+			{Name: "random1", Params: map[string]float64{"depth": 6, "seed": 0}},
+			{Name: "baseline"},
+		}},
+		{3, 1, 0, []common.BotSpec{
+			{Name: "random1", Params: map[string]float64{"depth": 6, "seed": 0}},
+			{Name: "random0", Params: map[string]float64{"seed": 7}},
+			{Name: "m"},
+		}},
+		{4, 0, 1, []common.BotSpec{
+			{Name: "baseline"},
+			{Name: "random1", Params: map[string]float64{"depth": 6, "seed": 1}},
+		}},
+	}
+	for _, test := range tests {
+		mi, bots, seat := o.setup(test.game, map[string]float64{"depth": 6})
+		if mi != test.mapIndex || seat != test.seat || !reflect.DeepEqual(bots, test.bots) {
+			t.Errorf("game %v: got map %v, seat %v, %v", test.game, mi, seat, bots)
+		}
+	}
+}
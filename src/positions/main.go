@@ -0,0 +1,148 @@
+package main
+
+import (
+	"common"
+	"flag"
+	"fmt"
+	"log"
+	"playground/arena"
+	"time"
+)
+
+var flagSuite = flag.String("suite", "", "Path to the suite of positions")
+var flagBots = flag.String("bots", "baseline", "Comma-separated list of bots to test, with parameters as in random1(depth=6)")
+var flagOracle = flag.String("oracle", "baseline", "Bot that plays the games out for positions without the best moves")
+var flagVerbose = flag.Bool("v", false, "Show the answer of every bot in every position")
+
+// The oracle plays every candidate river and the rest of the game, the
+// values are the final scores of the punter to move.
+type oracle struct {
+	bot    common.BotSpec
+	values map[[2]int]int64
+	best   int64
+	worst  int64
+}
+
+func makeOracle(pos *arena.Position, bot common.BotSpec) (o oracle, err error) {
+	o.bot = bot
+	o.values = make(map[[2]int]int64)
+	for i, r := range pos.Candidates() {
+		v, err := pos.Playout(claim(pos.Punter, r), bot)
+		if err != nil {
+			return o, err
+		}
+		o.values[r] = v
+		if i == 0 || v > o.best {
+			o.best = v
+		}
+		if i == 0 || v < o.worst {
+			o.worst = v
+		}
+	}
+	return o, nil
+}
+
+func claim(punter int, r [2]int) common.Move {
+	return common.Move{Claim: &common.ClaimMove{Punter: punter, Source: r[0], Target: r[1]}}
+}
+
+// Returns the value of the move in [0..1]: 1 for the best candidate, 0
+// for the worst one. Moves that are not candidates are played out.
+func (o *oracle) grade(pos *arena.Position, move common.Move) float64 {
+	var v int64
+	var ok bool
+	if c := move.Claim; c != nil {
+		if v, ok = o.values[[2]int{c.Source, c.Target}]; !ok {
+			v, ok = o.values[[2]int{c.Target, c.Source}]
+		}
+	}
+	if !ok {
+		var err error
+		if v, err = pos.Playout(move, o.bot); err != nil {
+			// An invalid move is as bad as it gets.
+			return 0
+		}
+	}
+	if o.best == o.worst {
+		if v >= o.best {
+			return 1
+		}
+		return 0
+	}
+	grade := float64(v-o.worst) / float64(o.best-o.worst)
+	if grade < 0 {
+		grade = 0
+	}
+	return grade
+}
+
+type summary struct {
+	solved  int
+	grades  float64
+	elapsed time.Duration
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Parse()
+
+	suite, err := arena.LoadSuite(*flagSuite)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(suite) == 0 {
+		log.Fatal("No positions in ", *flagSuite)
+	}
+	bots, err := common.ParseBotSpecs(*flagBots)
+	if err != nil {
+		log.Fatal(err)
+	}
+	oracleBot, err := common.ParseBotSpec(*flagOracle)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	summaries := make([]summary, len(bots))
+	for i := range suite {
+		pos := &suite[i]
+
+		var o *oracle
+		if len(pos.Best) == 0 {
+			oo, err := makeOracle(pos, oracleBot)
+			if err != nil {
+				log.Fatalf("%v: %v", pos.Name, err)
+			}
+			o = &oo
+		}
+
+		for j, bot := range bots {
+			move, elapsed, err := pos.Ask(bot)
+			if err != nil {
+				log.Fatalf("%v: %v", pos.Name, err)
+			}
+
+			grade := 0.0
+			if o != nil {
+				grade = o.grade(pos, move)
+			} else if pos.IsBest(move) {
+				grade = 1
+			}
+
+			s := &summaries[j]
+			if grade == 1 {
+				s.solved++
+			}
+			s.grades += grade
+			s.elapsed += elapsed
+			if *flagVerbose {
+				fmt.Printf("%v: %v %v, grade %.2f, %v\n", pos.Name, bot, move.String(), grade, elapsed)
+			}
+		}
+	}
+
+	for j, bot := range bots {
+		s := &summaries[j]
+		fmt.Printf("%v: solved %v of %v, average grade %.3f, time %v\n",
+			bot, s.solved, len(suite), s.grades/float64(len(suite)), s.elapsed)
+	}
+}
@@ -0,0 +1,200 @@
+// Package protocol implements the messages of the punter protocol and
+// their wire format, so that punters, servers and runners can share them.
+package protocol
+
+import (
+	"common"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"game"
+)
+
+// Handshake, punter -> server.
+type Me struct {
+	Me string `json:"me"`
+}
+
+// Handshake, server -> punter.
+type You struct {
+	You string `json:"you"`
+}
+
+// Setup, server -> punter.
+type Setup struct {
+	Punter   int           `json:"punter"`
+	Punters  int           `json:"punters"`
+	Map      common.Map    `json:"map"`
+	Settings game.Settings `json:"settings,omitempty"`
+}
+
+// Setup reply, punter -> server. The state is only sent in offline mode.
+type Ready struct {
+	Ready   int             `json:"ready"`
+	State   json.RawMessage `json:"state,omitempty"`
+	Futures []game.Future   `json:"futures,omitempty"`
+}
+
+// Gameplay, server -> punter: the last move of every punter.
+type Gameplay struct {
+	Move  common.Moves    `json:"move"`
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+// Move, punter -> server.
+type Move struct {
+	common.Move
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+type Score struct {
+	Punter int `json:"punter"`
+	Score  int `json:"score"`
+}
+
+type Stop struct {
+	Moves  []common.Move `json:"moves"`
+	Scores []Score       `json:"scores"`
+}
+
+// Scoring, server -> punter.
+type StopMessage struct {
+	Stop  Stop            `json:"stop"`
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+// Timeout, server -> punter.
+type Timeout struct {
+	Timeout float64 `json:"timeout"`
+}
+
+type Kind int
+
+const (
+	UnknownKind Kind = iota
+	SetupKind
+	GameplayKind
+	StopKind
+	TimeoutKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case SetupKind:
+		return "setup"
+	case GameplayKind:
+		return "gameplay"
+	case StopKind:
+		return "stop"
+	case TimeoutKind:
+		return "timeout"
+	}
+	return "unknown"
+}
+
+// ServerMessage is any message the server sends to a punter after the
+// handshake. Exactly one group of fields is set, see Kind.
+type ServerMessage struct {
+	Punter   *int           `json:"punter,omitempty"`
+	Punters  *int           `json:"punters,omitempty"`
+	Map      *common.Map    `json:"map,omitempty"`
+	Settings *game.Settings `json:"settings,omitempty"`
+
+	Move    *common.Moves `json:"move,omitempty"`
+	Stop    *Stop         `json:"stop,omitempty"`
+	Timeout *float64      `json:"timeout,omitempty"`
+
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+func (m *ServerMessage) Kind() Kind {
+	isSetup := m.Punter != nil || m.Punters != nil || m.Map != nil
+	kinds := 0
+	kind := UnknownKind
+	if isSetup {
+		kinds++
+		kind = SetupKind
+	}
+	if m.Move != nil {
+		kinds++
+		kind = GameplayKind
+	}
+	if m.Stop != nil {
+		kinds++
+		kind = StopKind
+	}
+	if m.Timeout != nil {
+		kinds++
+		kind = TimeoutKind
+	}
+	if kinds != 1 {
+		return UnknownKind
+	}
+	return kind
+}
+
+func (m *ServerMessage) Setup() (s Setup, err error) {
+	if m.Kind() != SetupKind {
+		return s, fmt.Errorf("not a setup message: %v", m.Kind())
+	}
+	if m.Punter == nil || m.Punters == nil || m.Map == nil {
+		return s, errors.New("setup message without punter, punters or map")
+	}
+	if *m.Punter < 0 || *m.Punter >= *m.Punters {
+		return s, fmt.Errorf("bad punter id %v for %v punters", *m.Punter, *m.Punters)
+	}
+	s.Punter = *m.Punter
+	s.Punters = *m.Punters
+	s.Map = *m.Map
+	if m.Settings != nil {
+		s.Settings = *m.Settings
+	}
+	return s, nil
+}
+
+func (m *ServerMessage) Gameplay() (g Gameplay, err error) {
+	if m.Kind() != GameplayKind {
+		return g, fmt.Errorf("not a gameplay message: %v", m.Kind())
+	}
+	g.Move = *m.Move
+	g.State = m.State
+	return g, nil
+}
+
+func (m *ServerMessage) StopMessage() (s StopMessage, err error) {
+	if m.Kind() != StopKind {
+		return s, fmt.Errorf("not a stop message: %v", m.Kind())
+	}
+	s.Stop = *m.Stop
+	s.State = m.State
+	return s, nil
+}
+
+func (m *ServerMessage) TimeoutMessage() (t Timeout, err error) {
+	if m.Kind() != TimeoutKind {
+		return t, fmt.Errorf("not a timeout message: %v", m.Kind())
+	}
+	t.Timeout = *m.Timeout
+	return t, nil
+}
+
+// Checks that exactly one kind of move is set.
+func ValidateMove(m *common.Move) error {
+	n := 0
+	if m.Claim != nil {
+		n++
+	}
+	if m.Pass != nil {
+		n++
+	}
+	if m.Splurge != nil {
+		n++
+	}
+	if m.Option != nil {
+		n++
+	}
+	if n != 1 {
+		return fmt.Errorf("a move must be exactly one of claim, pass, splurge or option, got %v", n)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type line struct {
+	from string // "P" for punter, "S" for server
+	text []byte
+}
+
+func readTranscript(t *testing.T) (lines []line) {
+	data, err := ioutil.ReadFile("testdata/transcript.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		lines = append(lines, line{from: l[:1], text: []byte(l[2:])})
+	}
+	return
+}
+
+// Returns a fresh value of the type of the punter's message.
+func punterMessage(text []byte) interface{} {
+	switch {
+	case bytes.HasPrefix(text, []byte(`{"me"`)):
+		return new(Me)
+	case bytes.HasPrefix(text, []byte(`{"ready"`)):
+		return new(Ready)
+	}
+	return new(Move)
+}
+
+func sameJSON(t *testing.T, a, b []byte) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatal(err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func TestTranscriptRoundTrip(t *testing.T) {
+	for _, l := range readTranscript(t) {
+		var message interface{}
+		if l.from == "P" {
+			message = punterMessage(l.text)
+		} else if bytes.HasPrefix(l.text, []byte(`{"you"`)) {
+			message = new(You)
+		} else {
+			message = new(ServerMessage)
+		}
+
+		frame := append([]byte(strconv.Itoa(len(l.text))+":"), l.text...)
+		if err := NewReader(bytes.NewReader(frame)).Recv(message); err != nil {
+			t.Fatalf("can't decode %s: %v", l.text, err)
+		}
+
+		var buf bytes.Buffer
+		w := bufio.NewWriter(&buf)
+		if err := Send(w, message); err != nil {
+			t.Fatal(err)
+		}
+		text, err := NewReader(&buf).ReadFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameJSON(t, l.text, text) {
+			t.Errorf("round trip changed the message:\n%s\n%s", l.text, text)
+		}
+	}
+}
+
+func TestServerMessageKinds(t *testing.T) {
+	var kinds []Kind
+	for _, l := range readTranscript(t)[2:] {
+		if l.from != "S" {
+			continue
+		}
+		var m ServerMessage
+		if err := json.Unmarshal(l.text, &m); err != nil {
+			t.Fatal(err)
+		}
+		kinds = append(kinds, m.Kind())
+
+		switch m.Kind() {
+		case SetupKind:
+			s, err := m.Setup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Punters != 2 || len(s.Map.Rivers) != 12 || !s.Settings.OptionsMode {
+				t.Errorf("bad setup: %+v", s)
+			}
+		case StopKind:
+			s, err := m.StopMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(s.Stop.Moves) != 2 || len(s.Stop.Scores) != 2 || len(s.State) == 0 {
+				t.Errorf("bad stop: %+v", s)
+			}
+		case TimeoutKind:
+			if to, err := m.TimeoutMessage(); err != nil || to.Timeout != 10 {
+				t.Errorf("bad timeout: %v %v", to, err)
+			}
+		}
+	}
+
+	want := []Kind{SetupKind, GameplayKind, GameplayKind, TimeoutKind, StopKind}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("kinds: got %v, want %v", kinds, want)
+	}
+}
+
+func TestMoves(t *testing.T) {
+	for _, l := range readTranscript(t) {
+		if l.from != "P" || bytes.HasPrefix(l.text, []byte(`{"me"`)) || bytes.HasPrefix(l.text, []byte(`{"ready"`)) {
+			continue
+		}
+		var m Move
+		if err := json.Unmarshal(l.text, &m); err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidateMove(&m.Move); err != nil {
+			t.Errorf("%s: %v", l.text, err)
+		}
+	}
+
+	var m Move
+	if err := json.Unmarshal([]byte(`{"pass":{"punter":0},"claim":{"punter":0,"source":1,"target":2}}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	if ValidateMove(&m.Move) == nil {
+		t.Error("a move with both pass and claim is valid")
+	}
+}
+
+func TestServerMessageErrors(t *testing.T) {
+	tests := []string{
+		`{}`,
+		`{"move":{"moves":[]},"timeout":1}`,
+		`{"punter":0}`,
+	}
+	for _, text := range tests {
+		var m ServerMessage
+		if err := json.Unmarshal([]byte(text), &m); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Setup(); err == nil {
+			t.Errorf("%v: setup decoded without errors", text)
+		}
+	}
+}
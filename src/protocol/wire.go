@@ -0,0 +1,140 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// The largest message we agree to read. The biggest maps with the
+// offline state of a bot take a few megabytes.
+const MaxMessageSize = 64 << 20
+
+// Encodes the message in the wire format: the length of the JSON text,
+// a colon and the JSON text itself.
+func Encode(message interface{}) ([]byte, error) {
+	bs, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode message: %v", err)
+	}
+	return append([]byte(strconv.Itoa(len(bs))+":"), bs...), nil
+}
+
+func Send(w *bufio.Writer, message interface{}) error {
+	bs, err := Encode(message)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(bs); err != nil {
+		return fmt.Errorf("can't send message: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("can't send message: %v", err)
+	}
+	return nil
+}
+
+// FrameError is a malformed or truncated message, Offset is the number
+// of bytes read from the stream before the problem.
+type FrameError struct {
+	Offset int64
+	Msg    string
+}
+
+func (e *FrameError) Error() string {
+	return fmt.Sprintf("bad message at byte %d: %s", e.Offset, e.Msg)
+}
+
+// Reader reads messages in the wire format and keeps track of the
+// offset in the stream for error reporting.
+type Reader struct {
+	r       *bufio.Reader
+	offset  int64
+	MaxSize int
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r), MaxSize: MaxMessageSize}
+}
+
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+func (r *Reader) errorf(format string, args ...interface{}) error {
+	return &FrameError{Offset: r.offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+func (r *Reader) readLength() (int, error) {
+	n, digits := 0, 0
+	for {
+		b, err := r.r.ReadByte()
+		if err == io.EOF {
+			if digits == 0 {
+				return 0, r.errorf("unexpected end of stream, expected a message")
+			}
+			return 0, r.errorf("unexpected end of stream in message length")
+		}
+		if err != nil {
+			return 0, r.errorf("can't read message length: %v", err)
+		}
+
+		switch {
+		case isSpace(b) && digits == 0:
+			// lamduct separates messages with newlines.
+		case b >= '0' && b <= '9':
+			n = 10*n + int(b-'0')
+			digits++
+			if n > r.MaxSize {
+				return 0, r.errorf("message is longer than %d bytes", r.MaxSize)
+			}
+		case b == ':' && digits > 0:
+			r.offset++
+			return n, nil
+		default:
+			return 0, r.errorf("unexpected byte %q in message length", b)
+		}
+		r.offset++
+	}
+}
+
+// Reads a single message and returns its JSON text.
+func (r *Reader) ReadFrame() ([]byte, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]byte, n)
+	read, err := io.ReadFull(r.r, bs)
+	r.offset += int64(read)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, r.errorf("stream ended after %d of %d bytes of the message", read, n)
+	}
+	if err != nil {
+		return nil, r.errorf("can't read message: %v", err)
+	}
+	return bs, nil
+}
+
+func (r *Reader) Recv(message interface{}) error {
+	bs, err := r.ReadFrame()
+	if err != nil {
+		return err
+	}
+	start := r.offset - int64(len(bs))
+
+	if err := json.Unmarshal(bs, message); err != nil {
+		if se, ok := err.(*json.SyntaxError); ok {
+			return &FrameError{Offset: start + se.Offset, Msg: "can't decode message: " + se.Error()}
+		}
+		return &FrameError{Offset: start, Msg: fmt.Sprintf("can't decode message: %v", err)}
+	}
+	return nil
+}
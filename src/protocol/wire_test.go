@@ -0,0 +1,72 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadFrame(t *testing.T) {
+	r := NewReader(strings.NewReader("2:{}\n 7:{\"a\":1}\r\n\t3:[1]"))
+	for _, want := range []string{`{}`, `{"a":1}`, `[1]`} {
+		bs, err := r.ReadFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(bs) != want {
+			t.Errorf("got %s, want %s", bs, want)
+		}
+	}
+	if r.Offset() != 23 {
+		t.Errorf("offset: got %v, want 23", r.Offset())
+	}
+	if _, err := r.ReadFrame(); err == nil {
+		t.Error("read a message after the end of stream")
+	}
+}
+
+func TestReadFrameErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset int64
+	}{
+		{"", 0},
+		{"  ", 2},
+		{":{}", 0},
+		{"12", 2},
+		{"1x:{}", 1},
+		{"-1:{}", 0},
+		{"1 :{}", 1},
+		{"2:{}3:", 6},
+		{"10:{}", 5},
+		{"99999999999:{}", 6},
+	}
+	for _, test := range tests {
+		r := NewReader(strings.NewReader(test.input))
+		r.MaxSize = 1 << 20
+		var err error
+		for err == nil {
+			_, err = r.ReadFrame()
+		}
+		fe, ok := err.(*FrameError)
+		if !ok {
+			t.Errorf("%q: not a frame error: %v", test.input, err)
+			continue
+		}
+		if fe.Offset != test.offset {
+			t.Errorf("%q: offset of %q: got %v, want %v", test.input, fe.Msg, fe.Offset, test.offset)
+		}
+	}
+}
+
+func TestRecvSyntaxError(t *testing.T) {
+	r := NewReader(strings.NewReader(`2:{}  8:{"me":x}`))
+	var me Me
+	if err := r.Recv(&me); err != nil {
+		t.Fatal(err)
+	}
+	err := r.Recv(&me)
+	fe, ok := err.(*FrameError)
+	if !ok || fe.Offset != 15 {
+		t.Errorf("got %v, want an error at byte 15", err)
+	}
+}
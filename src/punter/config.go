@@ -0,0 +1,85 @@
+package main
+
+import (
+	"common"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"game"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	defaultBot = "random1"
+	autoBot    = "auto" // the meta bot, which chooses the bot from the map and settings
+	configName = "punter.json"
+)
+
+var flagBot = flag.String("bot", "", "Bot to play with, or \"auto\" to choose it from the map and settings")
+var flagParams = flag.String("params", "", "Comma-separated list of bot parameters, e.g. depth=6,discount=0.9")
+
+// Config chooses the bot. The values come from the command line, the
+// PUNTER_BOT and PUNTER_PARAMS environment variables or punter.json next
+// to the binary, in this order of preference. Parameters given for a bot
+// are dropped when a more preferred source chooses another bot.
+type Config struct {
+	Bot    string             `json:"bot"`
+	Params map[string]float64 `json:"params,omitempty"`
+}
+
+func (c *Config) override(bot, params string) error {
+	if bot != "" {
+		c.Bot = bot
+		c.Params = nil
+	}
+	if params != "" {
+		ps, err := common.ParseParams(params)
+		if err != nil {
+			return err
+		}
+		c.Params = ps
+	}
+	return nil
+}
+
+func (c *Config) Validate() error {
+	if c.Bot == autoBot {
+		if len(c.Params) > 0 {
+			return fmt.Errorf("parameters can't be used with the %v bot", autoBot)
+		}
+		return nil
+	}
+	_, err := game.MakePlayerWithParams(c.Bot, c.Params)
+	return err
+}
+
+// Merges the config file (if any), the environment and the flags.
+func makeConfig(file []byte, getenv func(string) string, bot, params string) (c Config, err error) {
+	c.Bot = defaultBot
+	if file != nil {
+		if err = json.Unmarshal(file, &c); err != nil {
+			return c, fmt.Errorf("can't parse %v: %v", configName, err)
+		}
+	}
+	if err = c.override(getenv("PUNTER_BOT"), getenv("PUNTER_PARAMS")); err != nil {
+		return
+	}
+	if err = c.override(bot, params); err != nil {
+		return
+	}
+	return c, c.Validate()
+}
+
+func loadConfig() (Config, error) {
+	var file []byte
+	if exe, err := os.Executable(); err == nil {
+		path := filepath.Join(filepath.Dir(exe), configName)
+		file, err = ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return Config{}, fmt.Errorf("can't read %v: %v", path, err)
+		}
+	}
+	return makeConfig(file, os.Getenv, *flagBot, *flagParams)
+}
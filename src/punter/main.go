@@ -4,89 +4,22 @@ import (
 	"bufio"
 	"common"
 	"encoding/json"
-	"game"
-	"io"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"protocol"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
 	name = "MIPT Lambda"
 )
 
-type Me struct {
-	Me string `json:"me"`
-}
-
-type You struct {
-	You string `json:"you"`
-}
-
-type Ready struct {
-	Ready   int                 `json:"ready"`
-	State   *common.PlayerProxy `json:"state"`
-	Futures []game.Future       `json:"futures,omitempty"`
-}
-
-type Score struct {
-	Punter int `json:"punter"`
-	Score  int `json:"score"`
-}
-
-type Stop struct {
-	Moves  []common.Move `json:"move"`
-	Scores []Score       `json:"scores"`
-}
-
-type Step struct {
-	Punter   *int          `json:"punter"`
-	Punters  *int          `json:"punters"`
-	Map      *common.Map   `json:"map"`
-	Settings game.Settings `json:"settings,omitempty"`
-
-	Moves   *common.Moves       `json:"move"`
-	Stop    *Stop               `json:"stop"`
-	State   *common.PlayerProxy `json:"state"`
-	Timeout *float64            `json:"timeout"`
-}
-
-func sendMessage(w *bufio.Writer, message interface{}) {
-	bs, err := json.Marshal(message)
-	if err != nil {
-		log.Fatal("Can't send message:", err)
-	}
-	ss := string(bs)
-
-	io.WriteString(w, strconv.Itoa(len(ss))+":"+ss)
-	w.Flush()
-	return
-}
-
-func recvMessage(r *bufio.Reader, message interface{}) {
-	length, err := r.ReadString(':')
-	if err != nil {
-		return
-	}
-
-	n, err := strconv.Atoi(length[0 : len(length)-1])
-	if err != nil {
-		return
-	}
-
-	bytes := make([]byte, n, n)
-	_, err = io.ReadFull(r, bytes)
-	if err != nil {
-		return
-	}
-
-	err = json.Unmarshal(bytes, message)
-	if err != nil {
-		log.Fatal("Can't receive message:", err, " [", string(bytes), "]")
-	}
-}
-
-func formatScores(punter int, scores []Score) string {
+func formatScores(punter int, scores []protocol.Score) string {
 	s := "["
 	for _, sc := range scores {
 		if len(s) > 1 {
@@ -102,7 +35,7 @@ func formatScores(punter int, scores []Score) string {
 	return s
 }
 
-func getRank(punter int, scores []Score) int {
+func getRank(punter int, scores []protocol.Score) int {
 	var myScore int
 	for _, sc := range scores {
 		if sc.Punter == punter {
@@ -118,64 +51,125 @@ func getRank(punter int, scores []Score) int {
 	return rank
 }
 
-func handshake(r *bufio.Reader, w *bufio.Writer, n string) {
-	me := Me{Me: name + ": " + n}
-	sendMessage(w, me)
+func handshake(r *protocol.Reader, w *bufio.Writer, n string) error {
+	me := protocol.Me{Me: name + ": " + n}
+	if err := protocol.Send(w, me); err != nil {
+		return err
+	}
 
-	var you You
-	recvMessage(r, &you)
+	var you protocol.You
+	if err := r.Recv(&you); err != nil {
+		return err
+	}
 
 	if me.Me != you.You {
-		log.Fatal("Handshake failed: expected:", me.Me, " received:", you.You)
+		return fmt.Errorf("handshake failed: expected: %v received: %v", me.Me, you.You)
 	}
+	return nil
 }
 
-func interact(r *bufio.Reader, w *bufio.Writer) {
-	pp := common.MakePlayerProxy("random1")
-	handshake(r, w, pp.Name())
-
-	var step Step
-	step.State = &pp
+// The time the process started, the server counts the time from here.
+var start = time.Now()
 
-	recvMessage(r, &step)
-	if step.Map != nil {
-		pp.Setup(*step.Punter, *step.Punters, step.Map, step.Settings)
-		log.Println("Punter id:", *step.Punter)
-		log.Println("Number of punters:", *step.Punters)
-		log.Println("Game map:", *step.Map)
-		log.Println("Settings:", step.Settings)
+func interact(r *protocol.Reader, w *bufio.Writer, config Config) error {
+	if err := handshake(r, w, config.Bot); err != nil {
+		return err
+	}
 
-		sendMessage(w, Ready{Ready: *step.Punter, State: &pp, Futures: pp.GetFutures()})
-		return
+	var msg protocol.ServerMessage
+	if err := r.Recv(&msg); err != nil {
+		return err
 	}
 
-	if step.Moves != nil {
-		move := pp.MakeMove(step.Moves.Moves)
+	switch msg.Kind() {
+	case protocol.SetupKind:
+		setup, err := msg.Setup()
+		if err != nil {
+			return err
+		}
+		if problems := setup.Map.Validate(); len(problems) > 0 {
+			return errors.New("bad map: " + strings.Join(problems, "; "))
+		}
+
+		bot := config.Bot
+		if bot == autoBot {
+			bot = "meta"
+		}
+		pp, err := common.MakePlayerProxyWithParams(bot, config.Params)
+		if err != nil {
+			return err
+		}
+		log.Println("Bot:", bot, config.Params)
+
+		pp.Setup(setup.Punter, setup.Punters, &setup.Map, setup.Settings)
+		log.Println("Punter id:", setup.Punter)
+		log.Println("Number of punters:", setup.Punters)
+		log.Println("Game map:", setup.Map)
+		log.Println("Settings:", setup.Settings)
+
+		state, err := json.Marshal(&pp)
+		if err != nil {
+			return fmt.Errorf("can't save state: %v", err)
+		}
+		return protocol.Send(w, protocol.Ready{Ready: setup.Punter, State: state, Futures: pp.GetFutures()})
+
+	case protocol.GameplayKind:
+		pp, err := common.LoadPlayerProxy(msg.State)
+		if err != nil {
+			return err
+		}
+
+		pp.Timing.Observe(pp.GetPunter(), msg.Move.Moves)
+		budget := pp.Timing.Budget(time.Since(start))
+		if pp.SetTimeBudget(budget) {
+			log.Println("Too many timeouts, switched to", pp.Bot)
+		}
+		log.Printf("Time budget: %v, timeouts: %v", budget, pp.Timing.Timeouts)
+
+		move := pp.MakeMove(msg.Move.Moves)
 		log.Printf("Making move: %v", move.String())
-		sendMessage(w, move)
-		return
-	}
+		pp.Timing.Record(&move, time.Since(start))
 
-	if step.Stop != nil {
-		punter := step.State.GetPunter()
-		log.Println("Final scores:", formatScores(punter, step.Stop.Scores))
-		log.Printf("Rank: %d/%d\n", getRank(punter, step.Stop.Scores), len(step.Stop.Scores))
-		return
-	}
+		state, err := json.Marshal(&pp)
+		if err != nil {
+			return fmt.Errorf("can't save state: %v", err)
+		}
+		return protocol.Send(w, protocol.Move{Move: move, State: state})
+
+	case protocol.StopKind:
+		punter := -1
+		if len(msg.State) > 0 {
+			pp, err := common.LoadPlayerProxy(msg.State)
+			if err != nil {
+				return err
+			}
+			punter = pp.GetPunter()
+		}
+		log.Println("Final scores:", formatScores(punter, msg.Stop.Scores))
+		log.Printf("Rank: %d/%d\n", getRank(punter, msg.Stop.Scores), len(msg.Stop.Scores))
+		return nil
 
-	if step.Timeout != nil {
-		log.Println("Timeout: ", *step.Timeout)
-		return
+	case protocol.TimeoutKind:
+		log.Println("Timeout: ", *msg.Timeout)
+		return nil
 	}
 
-	log.Println("Unknown state")
+	return errors.New("unknown message from the server")
 }
 
 func main() {
 	log.SetFlags(0)
+	flag.Parse()
 
-	reader := bufio.NewReader(os.Stdin)
+	config, err := loadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	reader := protocol.NewReader(os.Stdin)
 	writer := bufio.NewWriter(os.Stdout)
 
-	interact(reader, writer)
+	if err := interact(reader, writer, config); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"common"
+	"game"
+	"protocol"
+	"strings"
+	"testing"
+)
+
+func frame(s string) string {
+	bs, _ := protocol.Encode(rawJSON(s))
+	return string(bs)
+}
+
+type rawJSON string
+
+func (r rawJSON) MarshalJSON() ([]byte, error) { return []byte(r), nil }
+
+func run(input string) (string, error) {
+	return runWith(Config{Bot: "random1"}, input)
+}
+
+func runWith(config Config, input string) (string, error) {
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	err := interact(protocol.NewReader(strings.NewReader(input)), w, config)
+	return out.String(), err
+}
+
+const you = `{"you":"MIPT Lambda: random1"}`
+
+func TestInteractErrors(t *testing.T) {
+	tests := []string{
+		"",
+		frame(you),
+		frame(you) + "12:{}",
+		frame(you) + frame(`{"punter":0,"punters":2,"map":{"sites":[{"id":1}],"rivers":[{"source":1,"target":2}],"mines":[1]}}`),
+		frame(you) + frame(`{"punter":2,"punters":2,"map":{"sites":[],"rivers":[],"mines":[]}}`),
+		frame(you) + frame(`{"move":{"moves":[]}}`),
+		frame(you) + frame(`{"what":1}`),
+	}
+	for _, input := range tests {
+		out, err := run(input)
+		if err == nil {
+			t.Errorf("%q: no error", input)
+		}
+		if out != frame(`{"me":"MIPT Lambda: random1"}`) {
+			t.Errorf("%q: replied after an error: %v", input, out)
+		}
+	}
+}
+
+func TestInteractSetup(t *testing.T) {
+	input := frame(you) + "\n" + frame(`{"punter":1,"punters":2,"map":{"sites":[{"id":1},{"id":2}],"rivers":[{"source":1,"target":2}],"mines":[1]}}`)
+	out, err := run(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := protocol.NewReader(strings.NewReader(out))
+	var me protocol.Me
+	var ready protocol.Ready
+	if err := r.Recv(&me); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Recv(&ready); err != nil {
+		t.Fatal(err)
+	}
+	if ready.Ready != 1 || len(ready.State) == 0 {
+		t.Errorf("bad ready message: %s", out)
+	}
+}
+
+func TestMakeConfig(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+
+	c, err := makeConfig(nil, getenv, "", "")
+	if err != nil || c.Bot != defaultBot {
+		t.Errorf("default config: got %v %v", c, err)
+	}
+
+	file := []byte(`{"bot": "random1", "params": {"depth": 6}}`)
+	c, err = makeConfig(file, getenv, "", "")
+	if err != nil || c.Bot != "random1" || c.Params["depth"] != 6 {
+		t.Errorf("config from file: got %v %v", c, err)
+	}
+
+	env["PUNTER_PARAMS"] = "depth=3,discount=0.5"
+	c, err = makeConfig(file, getenv, "", "")
+	if err != nil || c.Params["depth"] != 3 || c.Params["discount"] != 0.5 {
+		t.Errorf("params from env: got %v %v", c, err)
+	}
+
+	// Another bot drops the parameters of the less preferred sources.
+	c, err = makeConfig(file, getenv, "m", "")
+	if err != nil || c.Bot != "m" || len(c.Params) != 0 {
+		t.Errorf("bot from flags: got %v %v", c, err)
+	}
+
+	for _, bad := range [][2]string{{"m", "depth=3"}, {"nobody", ""}, {"auto", "depth=3"}, {"", "depth"}} {
+		if _, err := makeConfig(nil, getenv, bad[0], bad[1]); err == nil {
+			t.Errorf("config %v is valid", bad)
+		}
+	}
+}
+
+func TestInteractAuto(t *testing.T) {
+	config := Config{Bot: autoBot}
+	const meAuto = `{"you":"MIPT Lambda: auto"}`
+	setup := `{"punter":0,"punters":2,"map":{"sites":[{"id":1},{"id":2},{"id":3}],"rivers":[{"source":1,"target":2},{"source":2,"target":3}],"mines":[1]},"settings":{"futures":true}}`
+
+	out, err := runWith(config, frame(meAuto)+frame(setup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := protocol.NewReader(strings.NewReader(out))
+	var me protocol.Me
+	var ready protocol.Ready
+	if err := r.Recv(&me); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Recv(&ready); err != nil {
+		t.Fatal(err)
+	}
+
+	// The flags of the later turns don't matter, the bot comes from the state.
+	moves := `{"move":{"moves":[{"pass":{"punter":0}},{"pass":{"punter":1}}]},"state":` + string(ready.State) + `}`
+	out, err = runWith(Config{Bot: "m"}, frame(`{"you":"MIPT Lambda: m"}`)+frame(moves))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = protocol.NewReader(strings.NewReader(out))
+	var move protocol.Move
+	if err := r.Recv(&me); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Recv(&move); err != nil {
+		t.Fatal(err)
+	}
+	pp, err := common.LoadPlayerProxy(move.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setupPP, err := common.LoadPlayerProxy(ready.State)
+	if err != nil {
+		t.Fatal(err)
+	}
+	choice := setupPP.Player.(*game.MetaPlayer).Choice
+	if meta, ok := pp.Player.(*game.MetaPlayer); !ok || pp.Bot != "meta" || meta.Choice != choice {
+		t.Errorf("bot after a move: got %v, want meta playing %v", pp.Bot, choice)
+	}
+}